@@ -0,0 +1,219 @@
+package gitconfig
+
+import (
+	"errors"
+)
+
+var (
+	MissingUpstreamError    = errors.New("no upstream configured for branch")
+	UntrackedUpstreamError  = errors.New("upstream branch not stored as a remote-tracking branch")
+	UnmatchedPushError      = errors.New("push refspecs do not include branch")
+	UntrackedPushError      = errors.New("push destination has no remote-tracking branch")
+	NothingPushError        = errors.New("push.default is nothing")
+	AmbiguousPushError      = errors.New("cannot resolve simple push to a single destination")
+	InvalidPushDefaultError = errors.New("invalid push.default value")
+)
+
+// Branch represents the configuration of a local git branch, given by a
+// "branch.<name>" section, together with the remote and push configuration
+// that determines its upstream and push destinations.
+type Branch interface {
+	// Name returns the name of the branch, such as "main".
+	Name() string
+
+	// Ref returns the full reference name of the branch, such as
+	// "refs/heads/main".
+	Ref() string
+
+	// Remote returns the name of the remote the branch is fetched from,
+	// given by "branch.<name>.remote", or the empty string if the branch has
+	// no remote. The remote "." is the local repository.
+	Remote() string
+
+	// PushRemote returns the name of the remote the branch is pushed to,
+	// given by "branch.<name>.pushRemote", "remote.pushDefault" or
+	// "branch.<name>.remote", in that order, or "origin" if none of these
+	// are set.
+	PushRemote() string
+
+	// Merge returns the references of the remote that the branch merges
+	// with, given by "branch.<name>.merge".
+	Merge() []string
+
+	// Upstream returns the upstream reference of the branch, as given by
+	// "git rev-parse <name>@{upstream}". This is the remote-tracking
+	// reference that the first reference of Merge is mapped to by the
+	// fetch refspecs of the remote, or the merge reference itself if the
+	// remote of the branch is ".". As with git, negative fetch refspecs are
+	// not applied. If the branch has no remote or merge reference, Upstream
+	// returns MissingUpstreamError, and if the merge reference is not mapped
+	// to a remote-tracking reference, Upstream returns
+	// UntrackedUpstreamError.
+	Upstream() (string, error)
+
+	// Push returns the remote-tracking reference of the destination that
+	// the branch is pushed to, as given by "git rev-parse <name>@{push}".
+	// The destination is given by the push refspecs of the push remote, if
+	// any, or by "push.default" otherwise, and is mapped to a
+	// remote-tracking reference by the fetch refspecs of the push remote,
+	// ignoring negative refspecs as git does. Push returns
+	// UnmatchedPushError if the push refspecs do not include the branch,
+	// UntrackedPushError if the destination has no remote-tracking
+	// reference, NothingPushError if "push.default" is "nothing", and
+	// AmbiguousPushError if "push.default" is "simple" and the destination
+	// is not the upstream of the branch. If the "push.default" value is not
+	// valid, Push returns a *PropertyError with InvalidPushDefaultError.
+	Push() (string, error)
+}
+
+// branch is the implementation of the Branch interface.
+type branch struct {
+	config Config
+	name   string
+}
+
+// Name returns the name of the branch.
+func (b branch) Name() string { return b.name }
+
+// Ref returns the full reference name of the branch.
+func (b branch) Ref() string { return "refs/heads/" + b.name }
+
+// Remote returns the name of the remote the branch is fetched from.
+func (b branch) Remote() string { return b.get("remote") }
+
+// PushRemote returns the name of the remote the branch is pushed to.
+func (b branch) PushRemote() string {
+	if _remote := b.get("pushremote"); _remote != "" {
+		return _remote
+	} else if _default := b.config.Get("remote.pushdefault"); _default != nil {
+		if _default.String() != "" {
+			return _default.String()
+		}
+	}
+	if _remote := b.Remote(); _remote != "" {
+		return _remote
+	}
+
+	return "origin"
+} // PushRemote()
+
+// Merge returns the references of the remote that the branch merges with.
+func (b branch) Merge() []string {
+	return values(b.config.GetAll("branch." + b.name + ".merge"))
+} // Merge()
+
+// Upstream returns the upstream reference of the branch.
+func (b branch) Upstream() (string, error) {
+	_name := b.Remote()
+	_merge := b.Merge()
+	if _name == "" || len(_merge) == 0 {
+		return "", MissingUpstreamError
+	}
+
+	// merging from the local repository uses the merge reference itself
+	if _name == "." {
+		return _merge[0], nil
+	}
+	_tracking, _ok := translate(newRemote(b.config, _name).valid(), _merge[0])
+	if !_ok {
+		return "", UntrackedUpstreamError
+	}
+
+	return _tracking, nil
+} // Upstream()
+
+// Push returns the remote-tracking reference of the destination that the
+// branch is pushed to.
+func (b branch) Push() (string, error) {
+	_remote := newRemote(b.config, b.PushRemote())
+
+	// are there push refspecs for the remote?
+	_refspecs, _err := _remote.PushRefspecs()
+	if _err != nil {
+		return "", _err
+	} else if len(_refspecs) != 0 {
+		_destination, _ok := translate(_refspecs, b.Ref())
+		if !_ok {
+			return "", UnmatchedPushError
+		}
+		return tracking(_remote, _destination)
+	} else if _remote.Mirror() {
+		return tracking(_remote, b.Ref())
+	}
+
+	// otherwise, the destination is given by push.default
+	//		- "tracking" is a deprecated synonym for "upstream"
+	//		- git defaults to "simple"
+	_default := "simple"
+	if _property := b.config.Get("push.default"); _property != nil {
+		_default = _property.String()
+	}
+	switch _default {
+	case "nothing":
+		return "", NothingPushError
+
+	case "matching", "current":
+		return tracking(_remote, b.Ref())
+
+	case "upstream", "tracking":
+		return b.Upstream()
+
+	case "simple":
+		_upstream, _err := b.Upstream()
+		if _err != nil {
+			return "", _err
+		}
+		_current, _err := tracking(_remote, b.Ref())
+		if _err != nil {
+			return "", _err
+		} else if _current != _upstream {
+			return "", AmbiguousPushError
+		}
+		return _current, nil
+	}
+
+	return "", &PropertyError{
+		Name: "push.default",
+		Err:  InvalidPushDefaultError,
+	}
+} // Push()
+
+// ensure branch conforms to the Branch interface
+var _ Branch = &branch{}
+
+//
+// private methods
+//
+
+// get returns the value of the "branch.<name>.<variable>" property of the
+// branch, or the empty string if the property is not defined.
+func (b branch) get(variable string) string {
+	_property := b.config.Get("branch." + b.name + "." + variable)
+	if _property == nil {
+		return ""
+	}
+
+	return _property.String()
+} // get()
+
+//
+// private functions
+//
+
+// newBranch returns the branch with the given name defined by the
+// configuration c.
+func newBranch(c Config, name string) Branch {
+	return &branch{config: c, name: name}
+} // newBranch()
+
+// tracking returns the remote-tracking reference for the push destination
+// ref of remote r, returning UntrackedPushError if the destination is not
+// mapped to a remote-tracking reference by the fetch refspecs of r.
+func tracking(r *remote, ref string) (string, error) {
+	_tracking, _ok := translate(r.valid(), ref)
+	if !_ok {
+		return "", UntrackedPushError
+	}
+
+	return _tracking, nil
+} // tracking()
@@ -0,0 +1,240 @@
+package gitconfig_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+// the local configuration of the branch tests
+var _BRANCHES = `
+[remote "origin"]
+	url = https://example.com/origin.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+	fetch = ^refs/heads/tmp/*
+[remote "fork"]
+	url = https://example.com/fork.git
+	fetch = +refs/heads/*:refs/remotes/fork/*
+[remote "bare"]
+	url = https://example.com/bare.git
+[branch "main"]
+	remote = origin
+	merge = refs/heads/main
+[branch "feature"]
+	remote = origin
+	merge = refs/heads/main
+[branch "local"]
+	remote = .
+	merge = refs/heads/main
+[branch "topic"]
+	remote = origin
+	merge = refs/heads/topic
+	pushRemote = fork
+[branch "tmp/x"]
+	remote = origin
+	merge = refs/heads/tmp/x
+[branch "lost"]
+	remote = bare
+	merge = refs/heads/main
+`
+
+func TestBranch(t *testing.T) {
+	_dir := repository(t, _BRANCHES)
+	defer os.RemoveAll(_dir)
+
+	for _, _test := range []struct {
+		overrides []string
+		branch    string
+		upstream  string
+		uerr      error
+		push      string
+		perr      error
+	}{
+		// push.default is simple by default
+		{
+			nil, "main",
+			"refs/remotes/origin/main", nil,
+			"refs/remotes/origin/main", nil,
+		},
+		{
+			nil, "feature",
+			"refs/remotes/origin/main", nil,
+			"", gitconfig.AmbiguousPushError,
+		},
+		{
+			nil, "local",
+			"refs/heads/main", nil,
+			"", gitconfig.UntrackedPushError,
+		},
+		{
+			nil, "topic",
+			"refs/remotes/origin/topic", nil,
+			"", gitconfig.AmbiguousPushError,
+		},
+		{
+			nil, "lost",
+			"", gitconfig.UntrackedUpstreamError,
+			"", gitconfig.UntrackedUpstreamError,
+		},
+		{
+			nil, "tmp/x",
+			"refs/remotes/origin/tmp/x", nil,
+			"refs/remotes/origin/tmp/x", nil,
+		},
+		{
+			nil, "orphan",
+			"", gitconfig.MissingUpstreamError,
+			"", gitconfig.MissingUpstreamError,
+		},
+
+		// each push.default mode
+		{
+			[]string{"push.default=nothing"}, "main",
+			"refs/remotes/origin/main", nil,
+			"", gitconfig.NothingPushError,
+		},
+		{
+			[]string{"push.default=matching"}, "feature",
+			"refs/remotes/origin/main", nil,
+			"refs/remotes/origin/feature", nil,
+		},
+		{
+			[]string{"push.default=current"}, "feature",
+			"refs/remotes/origin/main", nil,
+			"refs/remotes/origin/feature", nil,
+		},
+		{
+			[]string{"push.default=current"}, "topic",
+			"refs/remotes/origin/topic", nil,
+			"refs/remotes/fork/topic", nil,
+		},
+		{
+			[]string{"push.default=upstream"}, "feature",
+			"refs/remotes/origin/main", nil,
+			"refs/remotes/origin/main", nil,
+		},
+		{
+			[]string{"push.default=tracking"}, "topic",
+			"refs/remotes/origin/topic", nil,
+			"refs/remotes/origin/topic", nil,
+		},
+		{
+			[]string{"push.default=simple"}, "main",
+			"refs/remotes/origin/main", nil,
+			"refs/remotes/origin/main", nil,
+		},
+
+		// push remotes, push refspecs and mirrors
+		{
+			[]string{"remote.pushDefault=fork", "push.default=current"}, "main",
+			"refs/remotes/origin/main", nil,
+			"refs/remotes/fork/main", nil,
+		},
+		{
+			[]string{"remote.origin.push=refs/heads/main:refs/heads/release"},
+			"main",
+			"refs/remotes/origin/main", nil,
+			"refs/remotes/origin/release", nil,
+		},
+		{
+			[]string{"remote.origin.push=refs/heads/main:refs/heads/release"},
+			"feature",
+			"refs/remotes/origin/main", nil,
+			"", gitconfig.UnmatchedPushError,
+		},
+		{
+			[]string{"push.default=current"}, "tmp/x",
+			"refs/remotes/origin/tmp/x", nil,
+			"refs/remotes/origin/tmp/x", nil,
+		},
+		{
+			[]string{"remote.fork.mirror=true"}, "topic",
+			"refs/remotes/origin/topic", nil,
+			"refs/remotes/fork/topic", nil,
+		},
+	} {
+		_config, _err := gitconfig.NewWithOverrides(_dir, _test.overrides...)
+		if _err != nil {
+			t.Fatalf("%v: unexpected error: %s", _test.overrides, _err)
+		}
+		_branch := _config.Branch(_test.branch)
+		if _branch.Name() != _test.branch {
+			t.Errorf(
+				"%v %s: name mismatch; got %q",
+				_test.overrides, _test.branch, _branch.Name(),
+			)
+		}
+
+		_upstream, _err := _branch.Upstream()
+		if _upstream != _test.upstream || _err != _test.uerr {
+			t.Errorf(
+				"%v %s: upstream mismatch; expected %q (%v), got %q (%v)",
+				_test.overrides, _test.branch,
+				_test.upstream, _test.uerr, _upstream, _err,
+			)
+		}
+
+		_push, _err := _branch.Push()
+		if _push != _test.push || _err != _test.perr {
+			t.Errorf(
+				"%v %s: push mismatch; expected %q (%v), got %q (%v)",
+				_test.overrides, _test.branch,
+				_test.push, _test.perr, _push, _err,
+			)
+		}
+	}
+
+	// ensure invalid push.default values are reported
+	_config, _err := gitconfig.NewWithOverrides(_dir, "push.default=bogus")
+	if _err != nil {
+		t.Fatalf("unexpected error: %s", _err)
+	}
+	_, _err = _config.Branch("main").Push()
+	if _error, _ok := _err.(*gitconfig.PropertyError); !_ok {
+		t.Errorf("push.default: expected PropertyError, got %v", _err)
+	} else if _error.Err != gitconfig.InvalidPushDefaultError {
+		t.Errorf("push.default: expected InvalidPushDefaultError, got %v", _err)
+	}
+
+	// ensure the branch properties are resolved
+	_config, _err = gitconfig.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error: %s", _err)
+	}
+	for _, _test := range []struct {
+		branch string
+		remote string
+		push   string
+		merge  []string
+	}{
+		{"main", "origin", "origin", []string{"refs/heads/main"}},
+		{"topic", "origin", "fork", []string{"refs/heads/topic"}},
+		{"local", ".", ".", []string{"refs/heads/main"}},
+		{"orphan", "", "origin", []string{}},
+	} {
+		_branch := _config.Branch(_test.branch)
+		if _branch.Ref() != "refs/heads/"+_test.branch {
+			t.Errorf("%s: unexpected ref %q", _test.branch, _branch.Ref())
+		}
+		if _branch.Remote() != _test.remote {
+			t.Errorf(
+				"%s: remote mismatch; expected %q, got %q",
+				_test.branch, _test.remote, _branch.Remote(),
+			)
+		}
+		if _branch.PushRemote() != _test.push {
+			t.Errorf(
+				"%s: push remote mismatch; expected %q, got %q",
+				_test.branch, _test.push, _branch.PushRemote(),
+			)
+		}
+		if !reflect.DeepEqual(_branch.Merge(), _test.merge) {
+			t.Errorf(
+				"%s: merge mismatch; expected %v, got %v",
+				_test.branch, _test.merge, _branch.Merge(),
+			)
+		}
+	}
+} // TestBranch()
@@ -0,0 +1,367 @@
+package gitconfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	InvalidColorError = errors.New("invalid color value")
+)
+
+// ColorType identifies the type of a terminal color.
+type ColorType int
+
+const (
+	// NormalColor leaves the terminal color unchanged, and is the color of
+	// an unspecified foreground or background.
+	NormalColor ColorType = iota
+
+	// DefaultColor is the default color of the terminal.
+	DefaultColor
+
+	// ANSIColor is one of the eight standard ANSI colors.
+	ANSIColor
+
+	// BrightColor is the bright variant of one of the eight standard ANSI
+	// colors.
+	BrightColor
+
+	// Color256 is one of the colors of the 256-color palette.
+	Color256
+
+	// RGBColor is a 24-bit color.
+	RGBColor
+)
+
+// the standard ANSI color names, in palette order
+var _COLORS = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+}
+
+// TerminalColor is a single foreground or background color.
+type TerminalColor struct {
+	// Type is the type of the color.
+	Type ColorType
+
+	// Value is the palette index of ANSIColor and BrightColor colors, from
+	// 0 (black) to 7 (white), and of Color256 colors, from 0 to 255.
+	Value uint8
+
+	// R, G and B are the components of RGBColor colors.
+	R, G, B uint8
+}
+
+// Attribute is a terminal text attribute, given by its ANSI SGR code.
+type Attribute int
+
+const (
+	Bold      Attribute = 1
+	Dim       Attribute = 2
+	Italic    Attribute = 3
+	Underline Attribute = 4
+	Blink     Attribute = 5
+	Reverse   Attribute = 7
+	Strike    Attribute = 9
+)
+
+// the names of the text attributes, as used by git
+var _ATTRIBUTES = map[string]Attribute{
+	"bold":    Bold,
+	"dim":     Dim,
+	"italic":  Italic,
+	"ul":      Underline,
+	"blink":   Blink,
+	"reverse": Reverse,
+	"strike":  Strike,
+}
+
+// Color is a git color specification, such as "bold red ul" or
+// "#ff0000 black", as used by the "color.*" configuration properties.
+type Color struct {
+	// Foreground is the foreground color, given by the first color of the
+	// specification.
+	Foreground TerminalColor
+
+	// Background is the background color, given by the second color of the
+	// specification.
+	Background TerminalColor
+
+	// Attributes lists the text attributes turned on by the specification,
+	// in ascending order.
+	Attributes []Attribute
+
+	// Negated lists the text attributes turned off by the specification,
+	// such as by "nobold" or "no-ul", in ascending order.
+	Negated []Attribute
+
+	// Reset is true if the specification resets the terminal colors and
+	// attributes before applying its own, as given by "reset".
+	Reset bool
+}
+
+// ParseColor returns the Color for the git color specification v. The
+// specification is a whitespace-separated list of up to two colors, the
+// foreground and background, and any number of text attributes. Colors may
+// be given by name ("normal", "default", "red", "brightblue", etc), by
+// 256-color palette number, or as "#rrggbb". If v is not a valid color
+// specification, ParseColor returns InvalidColorError.
+func ParseColor(v string) (Color, error) {
+	_color := Color{}
+	_colors := 0
+	_attributes := make(map[Attribute]bool)
+	_negated := make(map[Attribute]bool)
+	for _, _word := range strings.Fields(v) {
+		// is this a color?
+		if _terminal, _ok := terminal(_word); _ok {
+			switch _colors {
+			case 0:
+				_color.Foreground = _terminal
+			case 1:
+				_color.Background = _terminal
+			default:
+				return Color{}, InvalidColorError
+			}
+			_colors++
+			continue
+		}
+
+		// is this a reset?
+		if _word == "reset" {
+			_color.Reset = true
+			continue
+		}
+
+		// is this a text attribute?
+		//		- attributes may be negated by a "no" or "no-" prefix
+		_name := _word
+		_negate := strings.HasPrefix(_name, "no")
+		if _negate {
+			_name = strings.TrimPrefix(strings.TrimPrefix(_name, "no"), "-")
+		}
+		_attribute, _ok := _ATTRIBUTES[_name]
+		if !_ok {
+			return Color{}, InvalidColorError
+		} else if _negate {
+			_negated[_attribute] = true
+		} else {
+			_attributes[_attribute] = true
+		}
+	}
+
+	_color.Attributes = attributes(_attributes)
+	_color.Negated = attributes(_negated)
+	return _color, nil
+} // ParseColor()
+
+// ANSI returns the ANSI escape sequence for the color, as generated by
+// "git config --type=color". If the color specifies neither colors nor
+// attributes, ANSI returns the empty string.
+func (c Color) ANSI() string {
+	_codes := make([]string, 0)
+	for _, _attribute := range c.Attributes {
+		_codes = append(_codes, strconv.Itoa(int(_attribute)))
+	}
+
+	// bold and dim share the same code when negated
+	_negated := make(map[int]bool)
+	for _, _attribute := range c.Negated {
+		_negated[negate(_attribute)] = true
+	}
+	_sorted := make([]int, 0, len(_negated))
+	for _code := range _negated {
+		_sorted = append(_sorted, _code)
+	}
+	sort.Ints(_sorted)
+	for _, _code := range _sorted {
+		_codes = append(_codes, strconv.Itoa(_code))
+	}
+
+	// add the colors
+	if _code := c.Foreground.ansi(3, 9); _code != "" {
+		_codes = append(_codes, _code)
+	}
+	if _code := c.Background.ansi(4, 10); _code != "" {
+		_codes = append(_codes, _code)
+	}
+
+	// a reset is given by an empty leading code
+	if c.Reset {
+		_codes = append([]string{""}, _codes...)
+	} else if len(_codes) == 0 {
+		return ""
+	}
+
+	return "\033[" + strings.Join(_codes, ";") + "m"
+} // ANSI()
+
+// String returns the git color specification of the color, such as
+// "bold red ul", which may be parsed by ParseColor.
+func (c Color) String() string {
+	_words := make([]string, 0)
+	if c.Reset {
+		_words = append(_words, "reset")
+	}
+	for _, _attribute := range c.Attributes {
+		_words = append(_words, _attribute.String())
+	}
+	for _, _attribute := range c.Negated {
+		_words = append(_words, "no"+_attribute.String())
+	}
+
+	// the background requires the foreground, even if it is normal
+	if c.Background.Type != NormalColor {
+		_words = append(_words, c.Foreground.String(), c.Background.String())
+	} else if c.Foreground.Type != NormalColor {
+		_words = append(_words, c.Foreground.String())
+	}
+
+	return strings.Join(_words, " ")
+} // String()
+
+// String returns the git name of the color, such as "red", "brightblue",
+// "196" or "#ff0000".
+func (t TerminalColor) String() string {
+	switch t.Type {
+	case DefaultColor:
+		return "default"
+	case ANSIColor:
+		return _COLORS[t.Value%8]
+	case BrightColor:
+		return "bright" + _COLORS[t.Value%8]
+	case Color256:
+		return strconv.Itoa(int(t.Value))
+	case RGBColor:
+		return fmt.Sprintf("#%02x%02x%02x", t.R, t.G, t.B)
+	}
+
+	return "normal"
+} // String()
+
+// String returns the git name of the attribute, such as "bold" or "ul".
+func (a Attribute) String() string {
+	for _name, _attribute := range _ATTRIBUTES {
+		if _attribute == a {
+			return _name
+		}
+	}
+
+	return strconv.Itoa(int(a))
+} // String()
+
+//
+// private methods
+//
+
+// ansi returns the ANSI SGR code for the color, given the code prefix for
+// standard colors (3 for foreground and 4 for background) and bright colors
+// (9 and 10 respectively). If the color is NormalColor, ansi returns the
+// empty string.
+func (t TerminalColor) ansi(standard, bright int) string {
+	switch t.Type {
+	case DefaultColor:
+		return strconv.Itoa(standard) + "9"
+	case ANSIColor:
+		return strconv.Itoa(standard) + strconv.Itoa(int(t.Value))
+	case BrightColor:
+		return strconv.Itoa(bright) + strconv.Itoa(int(t.Value))
+	case Color256:
+		return strconv.Itoa(standard) + "8;5;" + strconv.Itoa(int(t.Value))
+	case RGBColor:
+		return strconv.Itoa(standard) + "8;2;" +
+			strconv.Itoa(int(t.R)) + ";" +
+			strconv.Itoa(int(t.G)) + ";" +
+			strconv.Itoa(int(t.B))
+	}
+
+	return ""
+} // ansi()
+
+//
+// private functions
+//
+
+// terminal returns the TerminalColor for the given color name, returning
+// false if name is not a valid color. Color names are case-insensitive.
+func terminal(name string) (TerminalColor, bool) {
+	_name := strings.ToLower(name)
+	switch _name {
+	case "normal":
+		return TerminalColor{Type: NormalColor}, true
+	case "default":
+		return TerminalColor{Type: DefaultColor}, true
+	}
+
+	// is this a named color?
+	_type := ANSIColor
+	if strings.HasPrefix(_name, "bright") {
+		_type = BrightColor
+		_name = strings.TrimPrefix(_name, "bright")
+	}
+	for _i, _color := range _COLORS {
+		if _name == _color {
+			return TerminalColor{Type: _type, Value: uint8(_i)}, true
+		}
+	}
+	if _type == BrightColor {
+		return TerminalColor{}, false
+	}
+
+	// is this a 24-bit color?
+	if strings.HasPrefix(_name, "#") {
+		if len(_name) != 7 {
+			return TerminalColor{}, false
+		}
+		_rgb, _err := strconv.ParseUint(_name[1:], 16, 32)
+		if _err != nil {
+			return TerminalColor{}, false
+		}
+		return TerminalColor{
+			Type: RGBColor,
+			R:    uint8(_rgb >> 16),
+			G:    uint8(_rgb >> 8),
+			B:    uint8(_rgb),
+		}, true
+	}
+
+	// is this a palette number?
+	//		- -1 is normal, and the first 16 colors are the standard and
+	//		  bright ANSI colors
+	_n, _err := strconv.Atoi(_name)
+	if _err != nil || _n < -1 || _n > 255 {
+		return TerminalColor{}, false
+	} else if _n == -1 {
+		return TerminalColor{Type: NormalColor}, true
+	} else if _n < 8 {
+		return TerminalColor{Type: ANSIColor, Value: uint8(_n)}, true
+	} else if _n < 16 {
+		return TerminalColor{Type: BrightColor, Value: uint8(_n - 8)}, true
+	}
+
+	return TerminalColor{Type: Color256, Value: uint8(_n)}, true
+} // terminal()
+
+// attributes returns the ordered list of the attributes of the given set.
+func attributes(set map[Attribute]bool) []Attribute {
+	_attributes := make([]Attribute, 0, len(set))
+	for _attribute := range set {
+		_attributes = append(_attributes, _attribute)
+	}
+	sort.Slice(_attributes, func(i, j int) bool {
+		return _attributes[i] < _attributes[j]
+	})
+
+	return _attributes
+} // attributes()
+
+// negate returns the ANSI SGR code that turns off the given attribute.
+func negate(attribute Attribute) int {
+	if attribute == Bold {
+		return 22
+	}
+
+	return int(attribute) + 20
+} // negate()
@@ -0,0 +1,122 @@
+package gitconfig_test
+
+import (
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+var (
+	// define the color tests
+	//		- the expected escape sequences are those of
+	//		  "git config --type=color", with "!" marking invalid colors
+	_COLORS = map[string]string{
+		"":                                     "",
+		"red":                                  "\033[31m",
+		"bold red ul":                          "\033[1;4;31m",
+		"Red Blue":                             "\033[31;44m",
+		"brightblue":                           "\033[94m",
+		"#ff0000":                              "\033[38;2;255;0;0m",
+		"#FF00aa black":                        "\033[38;2;255;0;170;40m",
+		"#ff0000 #00ff00":                      "\033[38;2;255;0;0;48;2;0;255;0m",
+		"196":                                  "\033[38;5;196m",
+		"0":                                    "\033[30m",
+		"7 8":                                  "\033[37;100m",
+		"15":                                   "\033[97m",
+		"16":                                   "\033[38;5;16m",
+		"-1 3":                                 "\033[43m",
+		"normal red":                           "\033[41m",
+		"default default":                      "\033[39;49m",
+		"reset":                                "\033[m",
+		"reset red":                            "\033[;31m",
+		"nobold no-ul":                         "\033[22;24m",
+		"nodim nobold":                         "\033[22m",
+		"bold nobold":                          "\033[1;22m",
+		"italic strike dim blink reverse bold": "\033[1;2;3;5;7;9m",
+		"  red\tblue ":                         "\033[31;44m",
+		"BOLD":                                 "!",
+		"red blue green":                       "!",
+		"256":                                  "!",
+		"-2":                                   "!",
+		"#fff":                                 "!",
+		"no":                                   "!",
+		"brightdefault":                        "!",
+		"brightnormal":                         "!",
+	}
+)
+
+func TestPropertyColor(t *testing.T) {
+	for _v, _expected := range _COLORS {
+		_property := gitconfig.NewProperty("color.diff.meta", _v)
+		_color, _err := _property.Color()
+		if _expected == "!" {
+			if _err != gitconfig.InvalidColorError {
+				t.Fatalf(
+					"%q: unexpected error; expected %v, got %v",
+					_v, gitconfig.InvalidColorError, _err,
+				)
+			}
+			continue
+		} else if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _v, _err.Error())
+		} else if _color.ANSI() != _expected {
+			t.Fatalf(
+				"%q: unexpected escape sequence; expected %q, got %q",
+				_v, _expected, _color.ANSI(),
+			)
+		}
+
+		// ensure the color specification is equivalent
+		_parsed, _err := gitconfig.ParseColor(_color.String())
+		if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _color.String(), _err.Error())
+		} else if _parsed.ANSI() != _expected {
+			t.Fatalf(
+				"%q: unexpected escape sequence; expected %q, got %q",
+				_color.String(), _expected, _parsed.ANSI(),
+			)
+		}
+	}
+
+	// ensure properties without a value are rejected
+	_, _err := gitconfig.NewPropertyWithoutValue("color.ui").Color()
+	if _err != gitconfig.InvalidColorError {
+		t.Fatalf(
+			"unexpected error; expected %v, got %v",
+			gitconfig.InvalidColorError, _err,
+		)
+	}
+} // TestPropertyColor()
+
+func TestParseColor(t *testing.T) {
+	_color, _err := gitconfig.ParseColor("bold #0a0b0c brightgreen noul")
+	if _err != nil {
+		t.Fatalf("unexpected error from ParseColor(): %s", _err.Error())
+	}
+
+	_foreground := gitconfig.TerminalColor{
+		Type: gitconfig.RGBColor, R: 10, G: 11, B: 12,
+	}
+	_background := gitconfig.TerminalColor{
+		Type: gitconfig.BrightColor, Value: 2,
+	}
+	if _color.Foreground != _foreground {
+		t.Fatalf(
+			"unexpected foreground; expected %+v, got %+v",
+			_foreground, _color.Foreground,
+		)
+	} else if _color.Background != _background {
+		t.Fatalf(
+			"unexpected background; expected %+v, got %+v",
+			_background, _color.Background,
+		)
+	} else if len(_color.Attributes) != 1 ||
+		_color.Attributes[0] != gitconfig.Bold {
+		t.Fatalf("unexpected attributes: %v", _color.Attributes)
+	} else if len(_color.Negated) != 1 ||
+		_color.Negated[0] != gitconfig.Underline {
+		t.Fatalf("unexpected negated attributes: %v", _color.Negated)
+	} else if _color.Reset {
+		t.Fatal("unexpected reset; expected none")
+	}
+} // TestParseColor()
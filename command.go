@@ -0,0 +1,182 @@
+package gitconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	InvalidCommandConfigError = errors.New("invalid command configuration")
+)
+
+// NewCommandConfig returns the Config instance for the command git
+// configuration, the highest priority configuration given to git through
+// "git -c" and the environment. Properties are taken, in order, from the
+// GIT_CONFIG_COUNT, GIT_CONFIG_KEY_<n> and GIT_CONFIG_VALUE_<n> environment
+// variables, the GIT_CONFIG_PARAMETERS environment variable, and finally
+// from overrides. Each override takes the form of a "git -c" argument:
+// "name=value", or "name" for a property with no value.
+//
+// If the environment or overrides are malformed, InvalidCommandConfigError
+// is returned, while InvalidKeyError is returned for an invalid property
+// name.
+func NewCommandConfig(overrides ...string) (Config, error) {
+	_properties, _err := environment()
+	if _err != nil {
+		return nil, _err
+	}
+
+	// add the explicit overrides
+	for _, _override := range overrides {
+		_parts := strings.SplitN(_override, "=", 2)
+		_value := ""
+		if len(_parts) == 2 {
+			_value = _parts[1]
+		}
+		_property, _err := command(_parts[0], _value, len(_parts) == 1)
+		if _err != nil {
+			return nil, _err
+		}
+		_properties = append(_properties, _property)
+	}
+
+	return NewConfig(_properties), nil
+} // NewCommandConfig()
+
+//
+// private functions
+//
+
+// environment returns the list of command properties defined by the
+// environment.
+func environment() ([]Property, error) {
+	_properties := make([]Property, 0)
+
+	// extract the GIT_CONFIG_COUNT properties
+	_count := os.Getenv("GIT_CONFIG_COUNT")
+	if _count != "" {
+		_n, _err := strconv.ParseUint(_count, 10, 31)
+		if _err != nil {
+			return nil, InvalidCommandConfigError
+		}
+		for _i := 0; _i < int(_n); _i++ {
+			_key, _ok := os.LookupEnv(fmt.Sprintf("GIT_CONFIG_KEY_%d", _i))
+			if !_ok {
+				return nil, InvalidCommandConfigError
+			}
+			_value, _ok := os.LookupEnv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", _i))
+			if !_ok {
+				return nil, InvalidCommandConfigError
+			}
+			_property, _err := command(_key, _value, false)
+			if _err != nil {
+				return nil, _err
+			}
+			_properties = append(_properties, _property)
+		}
+	}
+
+	// extract the GIT_CONFIG_PARAMETERS properties
+	_parameters, _err := parameters(os.Getenv("GIT_CONFIG_PARAMETERS"))
+	if _err != nil {
+		return nil, _err
+	}
+
+	return append(_properties, _parameters...), nil
+} // environment()
+
+// parameters returns the list of command properties defined by the value of
+// the GIT_CONFIG_PARAMETERS environment variable. Each property is given as
+// a shell-quoted 'name'='value' pair, or in the older 'name=value' form,
+// separated by whitespace.
+func parameters(v string) ([]Property, error) {
+	_properties := make([]Property, 0)
+	_v := strings.TrimLeft(v, " \t\n")
+	for _v != "" {
+		_name, _rest, _ok := dequote(_v)
+		if !_ok {
+			return nil, InvalidCommandConfigError
+		}
+
+		// extract the value
+		//		- a name without "=" has no value, while a name followed
+		//		  by "=" alone has an empty value
+		_value := ""
+		_novalue := false
+		if _rest == "" || isspace(_rest[0]) {
+			// the older form includes the value with the name
+			_parts := strings.SplitN(_name, "=", 2)
+			_name = _parts[0]
+			if len(_parts) == 2 {
+				_value = _parts[1]
+			} else {
+				_novalue = true
+			}
+		} else if _rest[0] == '=' {
+			_rest = _rest[1:]
+			if _rest != "" && !isspace(_rest[0]) {
+				_value, _rest, _ok = dequote(_rest)
+				if !_ok {
+					return nil, InvalidCommandConfigError
+				}
+			}
+		} else {
+			return nil, InvalidCommandConfigError
+		}
+
+		_property, _err := command(_name, _value, _novalue)
+		if _err != nil {
+			return nil, _err
+		}
+		_properties = append(_properties, _property)
+		_v = strings.TrimLeft(_rest, " \t\n")
+	}
+
+	return _properties, nil
+} // parameters()
+
+// dequote removes the shell single-quoting from the start of v, returning
+// the unquoted string and the remainder of v. A single quote or exclamation
+// mark within the quoted string is given by closing the quotes, adding the
+// backslash-escaped character, and reopening the quotes. If v does not start
+// with a quoted string, dequote returns false.
+func dequote(v string) (string, string, bool) {
+	if !strings.HasPrefix(v, "'") {
+		return "", "", false
+	}
+
+	_unquoted := make([]byte, 0, len(v))
+	for _i := 1; _i < len(v); _i++ {
+		if v[_i] != '\'' {
+			_unquoted = append(_unquoted, v[_i])
+			continue
+		}
+
+		// we have the closing quote; is it followed by an escaped character
+		// and the start of another quoted string?
+		if _i+3 < len(v) && v[_i+1] == '\\' &&
+			(v[_i+2] == '\'' || v[_i+2] == '!') && v[_i+3] == '\'' {
+			_unquoted = append(_unquoted, v[_i+2])
+			_i += 3
+			continue
+		}
+		return string(_unquoted), v[_i+1:], true
+	}
+
+	return "", "", false
+} // dequote()
+
+// command returns the command property with the given name and value v. If
+// novalue is true, the property has no value. If the name is invalid,
+// command returns InvalidKeyError.
+func command(name, v string, novalue bool) (Property, error) {
+	_, _, _, _err := split(name)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return newProperty(name, v, novalue, CommandScope, "", 0), nil
+} // command()
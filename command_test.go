@@ -0,0 +1,213 @@
+package gitconfig_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+	"github.com/denormal/go-gittools"
+)
+
+type commandtest struct {
+	environment map[string]string
+	overrides   []string
+	properties  []string
+	err         error
+}
+
+var (
+	// define the command configuration tests
+	//		- properties are listed as "name=value" in definition order
+	_COMMANDS = []commandtest{
+		{nil, nil, []string{}, nil},
+		{
+			map[string]string{
+				"GIT_CONFIG_COUNT":   "2",
+				"GIT_CONFIG_KEY_0":   "Core.AutoCRLF",
+				"GIT_CONFIG_VALUE_0": "input",
+				"GIT_CONFIG_KEY_1":   "remote.Origin.url",
+				"GIT_CONFIG_VALUE_1": "a b=c",
+			},
+			nil,
+			[]string{"core.autocrlf=input", "remote.Origin.url=a b=c"},
+			nil,
+		},
+		{
+			map[string]string{
+				"GIT_CONFIG_PARAMETERS": "'user.name'='A '\\''N'\\'' Other' " +
+					"'old.style=x=y'  'bare.key' 'empty.value'=",
+			},
+			nil,
+			[]string{
+				"user.name=A 'N' Other",
+				"old.style=x=y",
+				"bare.key=",
+				"empty.value=",
+			},
+			nil,
+		},
+		{
+			map[string]string{
+				"GIT_CONFIG_COUNT":      "1",
+				"GIT_CONFIG_KEY_0":      "a.b",
+				"GIT_CONFIG_VALUE_0":    "count",
+				"GIT_CONFIG_PARAMETERS": "'a.b'='parameters'",
+			},
+			[]string{"a.b=override", "a.c"},
+			[]string{"a.b=count", "a.b=parameters", "a.b=override", "a.c="},
+			nil,
+		},
+		{map[string]string{"GIT_CONFIG_COUNT": "x"}, nil, nil, gitconfig.InvalidCommandConfigError},
+		{map[string]string{"GIT_CONFIG_COUNT": "1"}, nil, nil, gitconfig.InvalidCommandConfigError},
+		{
+			map[string]string{"GIT_CONFIG_COUNT": "1", "GIT_CONFIG_KEY_0": "a.b"},
+			nil, nil, gitconfig.InvalidCommandConfigError,
+		},
+		{map[string]string{"GIT_CONFIG_PARAMETERS": "a.b=c"}, nil, nil, gitconfig.InvalidCommandConfigError},
+		{map[string]string{"GIT_CONFIG_PARAMETERS": "'a.b"}, nil, nil, gitconfig.InvalidCommandConfigError},
+		{map[string]string{"GIT_CONFIG_PARAMETERS": "'a.b'x"}, nil, nil, gitconfig.InvalidCommandConfigError},
+		{nil, []string{"name=value"}, nil, gitconfig.InvalidKeyError},
+	}
+
+	// the environment variables used for command configuration
+	_ENVIRONMENT = []string{
+		"GIT_CONFIG_COUNT",
+		"GIT_CONFIG_KEY_0",
+		"GIT_CONFIG_VALUE_0",
+		"GIT_CONFIG_KEY_1",
+		"GIT_CONFIG_VALUE_1",
+		"GIT_CONFIG_PARAMETERS",
+	}
+)
+
+func TestNewCommandConfig(t *testing.T) {
+	defer environment(nil)()
+
+	for _i, _test := range _COMMANDS {
+		environment(_test.environment)
+		_config, _err := gitconfig.NewCommandConfig(_test.overrides...)
+		if _err != _test.err {
+			t.Fatalf(
+				"%d: unexpected error; expected %v, got %v",
+				_i, _test.err, _err,
+			)
+		} else if _err != nil {
+			continue
+		}
+
+		// ensure the properties are as expected
+		_all := _config.FindAll("*")
+		if len(_all) != len(_test.properties) {
+			t.Fatalf(
+				"%d: unexpected property count; expected %d, got %d",
+				_i, len(_test.properties), len(_all),
+			)
+		}
+		for _j, _property := range _all {
+			_got := _property.Name() + "=" + _property.String()
+			if _got != _test.properties[_j] {
+				t.Fatalf(
+					"%d: unexpected property; expected %q, got %q",
+					_i, _test.properties[_j], _got,
+				)
+			} else if _property.Scope() != gitconfig.CommandScope {
+				t.Fatalf(
+					"%d: unexpected scope; expected %s, got %s",
+					_i, gitconfig.CommandScope, _property.Scope(),
+				)
+			}
+		}
+	}
+
+	// ensure properties without a value are distinguished from empty values
+	environment(map[string]string{
+		"GIT_CONFIG_PARAMETERS": "'bare.key' 'empty.value'= 'old.empty='",
+	})
+	_config, _err := gitconfig.NewCommandConfig("override.bare", "override.empty=")
+	if _err != nil {
+		t.Fatalf("unexpected error from NewCommandConfig(): %s", _err.Error())
+	}
+	for _name, _expected := range map[string]bool{
+		"bare.key":       false,
+		"empty.value":    true,
+		"old.empty":      true,
+		"override.bare":  false,
+		"override.empty": true,
+	} {
+		if _config.Get(_name).HasValue() != _expected {
+			t.Fatalf(
+				"%q: unexpected HasValue(); expected %v, got %v",
+				_name, _expected, !_expected,
+			)
+		}
+	}
+} // TestNewCommandConfig()
+
+func TestGitConfigCommand(t *testing.T) {
+	// skip this test if git is not installed
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+	defer environment(nil)()
+
+	// ensure command properties take precedence
+	environment(map[string]string{
+		"GIT_CONFIG_PARAMETERS": "'user.name'='Environment' 'test.a'='a'",
+	})
+	_config, _err := gitconfig.NewWithOverrides("", "user.name=Override")
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewWithOverrides(): %s",
+			_err.Error(),
+		)
+	}
+	for _name, _expected := range map[string]string{
+		"user.name": "Override",
+		"test.a":    "a",
+	} {
+		_property := _config.Get(_name)
+		if _property == nil {
+			t.Fatalf("%q: unexpected nil property", _name)
+		} else if _property.String() != _expected {
+			t.Fatalf(
+				"%q: unexpected value; expected %q, got %q",
+				_name, _expected, _property.String(),
+			)
+		}
+	}
+	if len(_config.Command().GetAll("user.name")) != 2 {
+		t.Fatal("unexpected command configuration; expected two user.name")
+	}
+} // TestGitConfigCommand()
+
+//
+// helper functions
+//
+
+// environment replaces the command configuration environment variables
+// with those given, returning a function that restores the original
+// environment.
+func environment(env map[string]string) func() {
+	_original := make(map[string]*string)
+	for _, _name := range _ENVIRONMENT {
+		if _value, _ok := os.LookupEnv(_name); _ok {
+			_original[_name] = &_value
+		} else {
+			_original[_name] = nil
+		}
+		os.Unsetenv(_name)
+		if _value, _ok := env[_name]; _ok {
+			os.Setenv(_name, _value)
+		}
+	}
+
+	return func() {
+		for _name, _value := range _original {
+			if _value == nil {
+				os.Unsetenv(_name)
+			} else {
+				os.Setenv(_name, *_value)
+			}
+		}
+	}
+} // environment()
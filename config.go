@@ -15,9 +15,16 @@ type Config interface {
 
 	// Get attempts to retrieve the property with the specified name from the
 	// current configuration, returning the property or nil if no property with
-	// that name is found.
+	// that name is found. If the property has multiple values, Get returns
+	// the last value defined. As with git, section and variable names are
+	// case-insensitive, while subsection names are case-sensitive.
 	Get(name string) Property
 
+	// GetAll returns all values of the property with the specified name, in
+	// the order they were defined. If no property with that name is found,
+	// GetAll returns an empty list.
+	GetAll(name string) []Property
+
 	// Find returns the list of all configuration properties with names matching
 	// the given pattern. If the pattern ends with "*", the rest of the pattern
 	// will be treated as a prefix, with Find returning all properties whose
@@ -25,6 +32,26 @@ type Config interface {
 	// Get and looks for the exact property name.
 	Find(pattern string) []Property
 
+	// FindAll returns all values of the configuration properties with names
+	// matching the given pattern, in the order they were defined. Patterns
+	// are interpreted as for Find, so FindAll("*") returns every value
+	// in the configuration.
+	FindAll(pattern string) []Property
+
+	// Sections returns the names of the sections of the configuration, in
+	// the order they were first defined. Section names are lower-cased.
+	Sections() []string
+
+	// Subsections returns the names of the subsections of the given section,
+	// in the order they were first defined. The section name is
+	// case-insensitive, while subsection names are case-sensitive.
+	Subsections(section string) []string
+
+	// Section returns the configuration of the given section and subsection,
+	// retaining the full names of its properties. If subsection is "",
+	// Section returns the properties of the section that have no subsection.
+	Section(section, subsection string) Config
+
 	// String returns a string representation of the configuration, returning
 	// the properties in name order.
 	String() string
@@ -32,19 +59,30 @@ type Config interface {
 
 // config is the implementation of the git configuration block
 type config struct {
-	c   map[string]Property
-	all []Property
+	c       map[string]Property
+	values  map[string][]Property
+	all     []Property
+	ordered []Property
 }
 
 // NewConfig returns the configuration instance for the list of configuration
 // properties p. If p contains properties with the same name, the property
-// listed last will be the property set in the returned Config instance.
+// listed last will be the property returned by Get, while GetAll will return
+// every property with that name in the order given by p. As with git, the
+// section and variable names of properties are case-insensitive, while
+// subsection names are case-sensitive.
 func NewConfig(p []Property) Config {
-	// build the name -> property lookup as well as the "all" list
+	// build the name -> property lookups as well as the "all" list
+	//		- properties are keyed by their canonical name
 	c := &config{}
 	c.c = make(map[string]Property)
+	c.values = make(map[string][]Property)
+	c.ordered = make([]Property, 0, len(p))
 	for _, _p := range p {
-		c.c[_p.Name()] = _p
+		_name := canonical(_p.Name())
+		c.c[_name] = _p
+		c.values[_name] = append(c.values[_name], _p)
+		c.ordered = append(c.ordered, _p)
 	}
 
 	// extract the uniquely named properties
@@ -67,9 +105,10 @@ func (c config) All() []Property {
 
 // Get attempts to retrieve the property with the specified name from the
 // current configuration, returning the property or nil if no property with
-// that name is found.
+// that name is found. If the property has multiple values, Get returns the
+// last value defined.
 func (c config) Get(name string) Property {
-	_property, _ok := c.c[name]
+	_property, _ok := c.c[canonical(name)]
 	if _ok {
 		return _property
 	} else {
@@ -77,6 +116,17 @@ func (c config) Get(name string) Property {
 	}
 } // Get()
 
+// GetAll returns all values of the property with the specified name, in the
+// order they were defined. If no property with that name is found, GetAll
+// returns an empty list.
+func (c config) GetAll(name string) []Property {
+	_values := c.values[canonical(name)]
+	_properties := make([]Property, len(_values))
+	copy(_properties, _values)
+
+	return _properties
+} // GetAll()
+
 // Find returns the list of all configuration properties with names matching
 // the given pattern. If the pattern ends with "*", the rest of the pattern
 // will be treated as a prefix, with Find returning all properties whose name
@@ -99,7 +149,7 @@ func (c config) Find(pattern string) []Property {
 	_pattern := strings.TrimSuffix(pattern, "*")
 	_properties := []Property{}
 	for _, _property := range c.all {
-		if strings.HasPrefix(_property.Name(), _pattern) {
+		if prefix(canonical(_property.Name()), _pattern) {
 			_properties = append(_properties, _property)
 		}
 	}
@@ -107,6 +157,84 @@ func (c config) Find(pattern string) []Property {
 	return _properties
 } // Find()
 
+// FindAll returns all values of the configuration properties with names
+// matching the given pattern, in the order they were defined. If the pattern
+// ends with "*", the rest of the pattern will be treated as a prefix, with
+// FindAll returning all values of properties whose name shares the prefix.
+// If pattern does not end with "*", FindAll behaves as GetAll and looks for
+// the exact property name.
+func (c config) FindAll(pattern string) []Property {
+	// does the pattern end in "*"?
+	//		- if not, then this is just a GetAll() call in disguise
+	if !strings.HasSuffix(pattern, "*") {
+		return c.GetAll(pattern)
+	}
+
+	// otherwise, remove the '*' from the pattern and look for config
+	// property values with names that share the resulting prefix
+	_pattern := strings.TrimSuffix(pattern, "*")
+	_properties := []Property{}
+	for _, _property := range c.ordered {
+		if prefix(canonical(_property.Name()), _pattern) {
+			_properties = append(_properties, _property)
+		}
+	}
+
+	return _properties
+} // FindAll()
+
+// Sections returns the names of the sections of the configuration, in the
+// order they were first defined. Section names are lower-cased.
+func (c config) Sections() []string {
+	_sections := []string{}
+	_seen := make(map[string]bool)
+	for _, _property := range c.ordered {
+		_section := _property.Key().Section()
+		if !_seen[_section] {
+			_seen[_section] = true
+			_sections = append(_sections, _section)
+		}
+	}
+
+	return _sections
+} // Sections()
+
+// Subsections returns the names of the subsections of the given section, in
+// the order they were first defined. The section name is case-insensitive,
+// while subsection names are case-sensitive.
+func (c config) Subsections(section string) []string {
+	_section := strings.ToLower(section)
+	_subsections := []string{}
+	_seen := make(map[string]bool)
+	for _, _property := range c.ordered {
+		_key := _property.Key()
+		if _key.Section() != _section || _key.Subsection() == "" {
+			continue
+		} else if !_seen[_key.Subsection()] {
+			_seen[_key.Subsection()] = true
+			_subsections = append(_subsections, _key.Subsection())
+		}
+	}
+
+	return _subsections
+} // Subsections()
+
+// Section returns the configuration of the given section and subsection,
+// retaining the full names of its properties. If subsection is "", Section
+// returns the properties of the section that have no subsection.
+func (c config) Section(section, subsection string) Config {
+	_section := strings.ToLower(section)
+	_properties := []Property{}
+	for _, _property := range c.ordered {
+		_key := _property.Key()
+		if _key.Section() == _section && _key.Subsection() == subsection {
+			_properties = append(_properties, _property)
+		}
+	}
+
+	return NewConfig(_properties)
+} // Section()
+
 // String returns a string representation of the configuration, returning
 // the properties in name order.
 func (c config) String() string {
@@ -122,3 +250,50 @@ func (c config) String() string {
 
 // ensure config conforms to the Config interface
 var _ Config = &config{}
+
+//
+// private functions
+//
+
+// canonical returns the canonical form of the property name, following git's
+// rules: the section and variable names are lower-cased, while the
+// subsection name, between the first and last dots of name, is preserved.
+func canonical(name string) string {
+	_section, _subsection, _variable, _ok := fields(name)
+	if !_ok {
+		return strings.ToLower(name)
+	} else if _subsection == nil {
+		return strings.ToLower(_section) + "." + strings.ToLower(_variable)
+	}
+
+	return strings.ToLower(_section) + "." + *_subsection + "." +
+		strings.ToLower(_variable)
+} // canonical()
+
+// prefix returns true if the canonical property name starts with the given
+// pattern prefix. The section and variable names are compared
+// case-insensitively, while the subsection name must match exactly.
+func prefix(name, pattern string) bool {
+	if len(pattern) > len(name) {
+		return false
+	}
+
+	// the subsection lies between the section and variable names
+	_start, _end := 0, 0
+	_section, _subsection, _, _ := fields(name)
+	if _subsection != nil {
+		_start = len(_section) + 1
+		_end = _start + len(*_subsection)
+	}
+	for _i := 0; _i < len(pattern); _i++ {
+		if _i >= _start && _i < _end {
+			if pattern[_i] != name[_i] {
+				return false
+			}
+		} else if tolower(pattern[_i]) != name[_i] {
+			return false
+		}
+	}
+
+	return true
+} // prefix()
@@ -3,6 +3,7 @@ package gitconfig_test
 import (
 	"math/rand"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -25,7 +26,23 @@ var (
 		"p.k=1\n" +
 		"p.l=on\n" +
 		"p.m=yes\n" +
-		"p.n=true\n"
+		"p.n=true\n" +
+		"p.o=\n" +
+		"p.p=TRUE\n" +
+		"p.q=Off\n" +
+		"p.r=Yes\n" +
+		"p.s=10\n" +
+		"p.t=truthy\n"
+
+	// define a configuration with multi-valued properties
+	_MULTIPLE = []gitconfig.Property{
+		gitconfig.NewProperty("remote.origin.url", "https://example.com/x.git"),
+		gitconfig.NewProperty("remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"),
+		gitconfig.NewProperty("credential.helper", ""),
+		gitconfig.NewProperty("remote.origin.fetch", "+refs/tags/*:refs/tags/*"),
+		gitconfig.NewProperty("credential.helper", "cache"),
+		gitconfig.NewProperty("credential.helper", "store"),
+	}
 )
 
 func TestNewConfig(t *testing.T) {
@@ -196,3 +213,236 @@ func find(tests []*ptest, prefix string, t *testing.T) {
 		}
 	}
 } // find()
+
+func TestConfigGetAll(t *testing.T) {
+	_config := gitconfig.NewConfig(_MULTIPLE)
+
+	// ensure GetAll() returns every value in order
+	for _name, _values := range map[string][]string{
+		"remote.origin.fetch": {"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"},
+		"remote.origin.url":   {"https://example.com/x.git"},
+		"credential.helper":   {"", "cache", "store"},
+		"missing.property":    {},
+	} {
+		_all := _config.GetAll(_name)
+		if len(_all) != len(_values) {
+			t.Fatalf(
+				"%q: unexpected GetAll() size; expected %d, got %d",
+				_name, len(_values), len(_all),
+			)
+		}
+		for _i, _property := range _all {
+			if _property.String() != _values[_i] {
+				t.Fatalf(
+					"%q: unexpected GetAll() value; "+
+						"expected %q, got %q at index %d",
+					_name, _values[_i], _property.String(), _i,
+				)
+			}
+		}
+
+		// ensure Get() returns the last value
+		_get := _config.Get(_name)
+		if len(_values) == 0 {
+			if _get != nil {
+				t.Fatalf("%q: unexpected Get() property; expected nil", _name)
+			}
+		} else if _get == nil {
+			t.Fatalf("%q: unexpected nil property from Get()", _name)
+		} else if _get != _all[len(_all)-1] {
+			t.Fatalf(
+				"%q: unexpected Get() property; expected %q, got %q",
+				_name, _values[len(_values)-1], _get.String(),
+			)
+		}
+	}
+} // TestConfigGetAll()
+
+func TestConfigFindAll(t *testing.T) {
+	_config := gitconfig.NewConfig(_MULTIPLE)
+
+	// ensure FindAll() returns every value in definition order
+	for _pattern, _expected := range map[string][]int{
+		"*":                   {0, 1, 2, 3, 4, 5},
+		"remote.*":            {0, 1, 3},
+		"remote.origin.fetch": {1, 3},
+		"remote.":             {},
+		"credential.*":        {2, 4, 5},
+	} {
+		_all := _config.FindAll(_pattern)
+		if len(_all) != len(_expected) {
+			t.Fatalf(
+				"%q: unexpected FindAll() size; expected %d, got %d",
+				_pattern, len(_expected), len(_all),
+			)
+		}
+		for _i, _property := range _all {
+			if _property != _MULTIPLE[_expected[_i]] {
+				t.Fatalf(
+					"%q: unexpected FindAll() property at index %d",
+					_pattern, _i,
+				)
+			}
+		}
+	}
+
+	// ensure Find() returns the uniquely named properties
+	_find := _config.Find("remote.*")
+	if len(_find) != 2 {
+		t.Fatalf(
+			"unexpected Find() size; expected %d, got %d",
+			2, len(_find),
+		)
+	}
+} // TestConfigFindAll()
+
+func TestConfigCase(t *testing.T) {
+	_config := gitconfig.NewConfig([]gitconfig.Property{
+		gitconfig.NewProperty("Core.AutoCRLF", "input"),
+		gitconfig.NewProperty("remote.Origin.URL", "https://example.com/x.git"),
+		gitconfig.NewProperty("remote.origin.url", "https://example.com/y.git"),
+		gitconfig.NewProperty("Remote.A.B.Fetch", "+refs/heads/*"),
+	})
+
+	// ensure section and variable names are canonicalised
+	_all := _config.FindAll("*")
+	for _i, _name := range []string{
+		"core.autocrlf", "remote.Origin.url", "remote.origin.url",
+		"remote.A.B.fetch",
+	} {
+		if _all[_i].Name() != _name {
+			t.Fatalf(
+				"unexpected property name; expected %q, got %q",
+				_name, _all[_i].Name(),
+			)
+		}
+	}
+
+	// ensure lookups ignore the case of section and variable names, but
+	// respect the case of subsection names
+	for _pattern, _expected := range map[string][]string{
+		"core.autocrlf":     {"input"},
+		"CORE.AutoCrlf":     {"input"},
+		"Remote.Origin.Url": {"https://example.com/x.git"},
+		"remote.origin.URL": {"https://example.com/y.git"},
+		"remote.ORIGIN.url": {},
+		"REMOTE.A.B.FETCH":  {"+refs/heads/*"},
+		"remote.a.b.fetch":  {},
+		"CORE.*":            {"input"},
+		"Core.Auto*":        {"input"},
+		"REMOTE.*": {
+			"https://example.com/x.git", "https://example.com/y.git",
+			"+refs/heads/*",
+		},
+		"remote.O*":        {"https://example.com/x.git"},
+		"remote.o*":        {"https://example.com/y.git"},
+		"remote.Origin.U*": {"https://example.com/x.git"},
+		"remote.A.B.F*":    {"+refs/heads/*"},
+		"remote.a.*":       {},
+	} {
+		_all := _config.FindAll(_pattern)
+		if len(_all) != len(_expected) {
+			t.Fatalf(
+				"%q: unexpected FindAll() size; expected %d, got %d",
+				_pattern, len(_expected), len(_all),
+			)
+		}
+		for _i, _property := range _all {
+			if _property.String() != _expected[_i] {
+				t.Fatalf(
+					"%q: unexpected FindAll() value; expected %q, got %q",
+					_pattern, _expected[_i], _property.String(),
+				)
+			}
+		}
+	}
+} // TestConfigCase()
+
+func TestConfigSections(t *testing.T) {
+	_config := gitconfig.NewConfig([]gitconfig.Property{
+		gitconfig.NewProperty("core.bare", "false"),
+		gitconfig.NewProperty("remote.origin.url", "https://example.com/x.git"),
+		gitconfig.NewProperty("Branch.main.remote", "origin"),
+		gitconfig.NewProperty("remote.Upstream.url", "https://example.com/y.git"),
+		gitconfig.NewProperty("url.https://example.com/.insteadOf", "ex:"),
+		gitconfig.NewProperty("remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"),
+		gitconfig.NewProperty("remote.pushDefault", "origin"),
+		gitconfig.NewProperty("core.autocrlf", "input"),
+	})
+
+	// ensure the sections and subsections are ordered and unique
+	for _section, _expected := range map[string][]string{
+		"":       {"core", "remote", "branch", "url"},
+		"remote": {"origin", "Upstream"},
+		"Branch": {"main"},
+		"url":    {"https://example.com/"},
+		"core":   {},
+		"other":  {},
+	} {
+		var _got []string
+		if _section == "" {
+			_got = _config.Sections()
+		} else {
+			_got = _config.Subsections(_section)
+		}
+		if len(_got) != len(_expected) {
+			t.Fatalf(
+				"%q: unexpected sections; expected %q, got %q",
+				_section, _expected, _got,
+			)
+		}
+		for _i := range _got {
+			if _got[_i] != _expected[_i] {
+				t.Fatalf(
+					"%q: unexpected sections; expected %q, got %q",
+					_section, _expected, _got,
+				)
+			}
+		}
+	}
+
+	// ensure Section() returns the properties of a single section
+	for _names, _expected := range map[[2]string]string{
+		{"remote", "origin"}: "remote.origin.fetch=+refs/heads/*:refs/remotes/origin/*\n" +
+			"remote.origin.url=https://example.com/x.git\n",
+		{"REMOTE", ""}:       "remote.pushdefault=origin\n",
+		{"remote", "ORIGIN"}: "",
+		{"core", ""}:         "core.autocrlf=input\ncore.bare=false\n",
+	} {
+		_got := _config.Section(_names[0], _names[1]).String()
+		if _got != _expected {
+			t.Fatalf(
+				"%q: unexpected section; expected %q, got %q",
+				_names, _expected, _got,
+			)
+		}
+	}
+} // TestConfigSections()
+
+func TestConfigConcurrency(t *testing.T) {
+	// share a single configuration between goroutines
+	//		- run with -race to detect unsafe access to properties
+	_config := gitconfig.NewConfig(_ALL)
+	var _wait sync.WaitGroup
+	for _i := 0; _i < 8; _i++ {
+		_wait.Add(1)
+		go func() {
+			defer _wait.Done()
+			for _, _name := range _ORDER {
+				_property := _config.Get(_name)
+				_property.Bool()
+				_property.Int()
+				_property.List()
+				_config.GetAll(_name)
+			}
+			_config.Find("p.*")
+			_config.FindAll("*")
+			_config.Sections()
+			_config.Section("p", "")
+			if _config.String() != _STRING {
+				t.Errorf("unexpected configuration: %q", _config.String())
+			}
+		}()
+	}
+	_wait.Wait()
+} // TestConfigConcurrency()
@@ -0,0 +1,167 @@
+package gitconfig
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// span is a single component of a relative date expression, such as the
+// "2.weeks" of "2.weeks.3.days.ago".
+type span struct {
+	n    int
+	unit string
+}
+
+// the units of relative date expressions, in seconds; months and years are
+// applied to calendar dates when computing dates, and are otherwise treated
+// as 30 and 365 days
+var _UNITS = map[string]int64{
+	"second": 1,
+	"minute": 60,
+	"hour":   60 * 60,
+	"day":    24 * 60 * 60,
+	"week":   7 * 24 * 60 * 60,
+	"month":  30 * 24 * 60 * 60,
+	"year":   365 * 24 * 60 * 60,
+}
+
+// the layouts of absolute dates; dates without a time zone are in the
+// location of the reference time
+var _LAYOUTS = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"2006.01.02",
+}
+
+// approxidate returns the time represented by the date expression v, using
+// the subset of git's approxidate syntax that is used for expiry dates:
+// absolute dates such as "2006-01-02 15:04:05", Unix timestamps given as
+// "@seconds", and dates relative to now such as "90.days.ago", "2 weeks" and
+// "yesterday". If v is not a valid date expression, approxidate returns
+// false.
+func approxidate(v string, now time.Time) (time.Time, bool) {
+	_v := strings.TrimSpace(v)
+
+	// is this a Unix timestamp?
+	if strings.HasPrefix(_v, "@") {
+		_seconds, _err := strconv.ParseInt(_v[1:], 10, 64)
+		if _err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(_seconds, 0), true
+	}
+
+	// is this an absolute date?
+	for _, _layout := range _LAYOUTS {
+		_time, _err := time.ParseInLocation(_layout, _v, now.Location())
+		if _err == nil {
+			return _time, true
+		}
+	}
+
+	// otherwise, this should be a relative date
+	_spans, _ok := relative(_v)
+	if !_ok {
+		return time.Time{}, false
+	}
+	_time := now
+	for _, _span := range _spans {
+		switch _span.unit {
+		case "month":
+			_time = _time.AddDate(0, -_span.n, 0)
+		case "year":
+			_time = _time.AddDate(-_span.n, 0, 0)
+		default:
+			_seconds := time.Duration(_UNITS[_span.unit]) * time.Second
+			_time = _time.Add(-time.Duration(_span.n) * _seconds)
+		}
+	}
+
+	return _time, true
+} // approxidate()
+
+// approxispan returns the duration represented by the relative date
+// expression v, such as "2.weeks" or "90.days.ago", treating months as 30
+// days and years as 365 days. A plain integer is a number of seconds. If v
+// is not a valid relative date expression, approxispan returns false.
+func approxispan(v string) (time.Duration, bool) {
+	_v := strings.TrimSpace(v)
+	if _seconds, _err := strconv.ParseInt(_v, 10, 64); _err == nil {
+		return time.Duration(_seconds) * time.Second, true
+	}
+
+	_spans, _ok := relative(_v)
+	if !_ok {
+		return 0, false
+	}
+	_duration := time.Duration(0)
+	for _, _span := range _spans {
+		_seconds := time.Duration(_UNITS[_span.unit]) * time.Second
+		_duration += time.Duration(_span.n) * _seconds
+	}
+
+	return _duration, true
+} // approxispan()
+
+// relative returns the components of the relative date expression v, such
+// as "2.weeks.3.days.ago". Numbers and words may be separated by any
+// punctuation or whitespace, units may be singular or plural, and the
+// trailing "ago" is optional. The words "now" and "yesterday" are also
+// recognised. If v is not a valid relative date expression, relative
+// returns false.
+func relative(v string) ([]span, bool) {
+	_words := strings.FieldsFunc(strings.ToLower(v), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(_words) == 0 {
+		return nil, false
+	}
+
+	_spans := make([]span, 0)
+	_n := -1
+	for _, _word := range _words {
+		// is this a number?
+		if _int, _err := strconv.Atoi(_word); _err == nil {
+			if _n != -1 {
+				return nil, false
+			}
+			_n = _int
+			continue
+		}
+
+		switch _word {
+		case "ago", "now":
+			if _n != -1 {
+				return nil, false
+			}
+		case "yesterday":
+			if _n != -1 {
+				return nil, false
+			}
+			_spans = append(_spans, span{1, "day"})
+		default:
+			// is this a unit?
+			//		- units may be singular or plural
+			_unit := strings.TrimSuffix(_word, "s")
+			if _, _ok := _UNITS[_unit]; !_ok || _n == -1 {
+				return nil, false
+			}
+			_spans = append(_spans, span{_n, _unit})
+			_n = -1
+		}
+	}
+	if _n != -1 {
+		return nil, false
+	}
+
+	return _spans, true
+} // relative()
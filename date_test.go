@@ -0,0 +1,100 @@
+package gitconfig_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+func TestPropertyExpiryDate(t *testing.T) {
+	_now := time.Date(2020, time.March, 31, 12, 0, 0, 0, time.UTC)
+
+	for _v, _expected := range map[string]time.Time{
+		"never":                     gitconfig.ExpireNever,
+		"false":                     gitconfig.ExpireNever,
+		"now":                       gitconfig.ExpireAll,
+		"all":                       gitconfig.ExpireAll,
+		"90.days.ago":               _now.AddDate(0, 0, -90),
+		"90 days ago":               _now.AddDate(0, 0, -90),
+		"2.weeks":                   _now.AddDate(0, 0, -14),
+		"1.week.2.days.ago":         _now.AddDate(0, 0, -9),
+		"3.hours.30.minutes.ago":    _now.Add(-210 * time.Minute),
+		"1.second.ago":              _now.Add(-time.Second),
+		"1.month.ago":               _now.AddDate(0, -1, 0),
+		"2.years.ago":               _now.AddDate(-2, 0, 0),
+		"yesterday":                 _now.AddDate(0, 0, -1),
+		"@1500000000":               time.Unix(1500000000, 0),
+		"2019-06-01":                time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC),
+		"2019-06-01 10:30:00":       time.Date(2019, time.June, 1, 10, 30, 0, 0, time.UTC),
+		"2019-06-01 10:30:00 +0200": time.Date(2019, time.June, 1, 8, 30, 0, 0, time.UTC),
+		"":                          {},
+		"soon":                      {},
+		"2.fortnights.ago":          {},
+		"days.ago":                  {},
+		"2.3.days.ago":              {},
+		"2":                         {},
+	} {
+		_property := gitconfig.NewProperty("gc.reflogExpire", _v)
+		_got, _err := _property.ExpiryDate(_now)
+		if _expected.IsZero() {
+			if _err != gitconfig.InvalidExpiryError {
+				t.Fatalf(
+					"%q: unexpected error; expected %v, got %v",
+					_v, gitconfig.InvalidExpiryError, _err,
+				)
+			}
+			continue
+		} else if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _v, _err.Error())
+		} else if !_got.Equal(_expected) {
+			t.Fatalf(
+				"%q: unexpected date; expected %s, got %s",
+				_v, _expected, _got,
+			)
+		}
+	}
+} // TestPropertyExpiryDate()
+
+func TestPropertyDuration(t *testing.T) {
+	for _v, _expected := range map[string]time.Duration{
+		"900":               900 * time.Second,
+		"2.weeks":           14 * 24 * time.Hour,
+		"90.days.ago":       90 * 24 * time.Hour,
+		"1 hour 30 minutes": 90 * time.Minute,
+		"1.month":           30 * 24 * time.Hour,
+		"1.year":            365 * 24 * time.Hour,
+		"now":               0,
+		"never":             -1,
+		"2019-06-01":        -1,
+		"weeks":             -1,
+	} {
+		_property := gitconfig.NewProperty("credential.cacheTimeout", _v)
+		_got, _err := _property.Duration()
+		if _expected == -1 {
+			if _err != gitconfig.InvalidExpiryError {
+				t.Fatalf(
+					"%q: unexpected error; expected %v, got %v",
+					_v, gitconfig.InvalidExpiryError, _err,
+				)
+			}
+			continue
+		} else if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _v, _err.Error())
+		} else if _got != _expected {
+			t.Fatalf(
+				"%q: unexpected duration; expected %s, got %s",
+				_v, _expected, _got,
+			)
+		}
+	}
+
+	// ensure properties without a value are rejected
+	_, _err := gitconfig.NewPropertyWithoutValue("gc.pruneExpire").Duration()
+	if _err != gitconfig.InvalidExpiryError {
+		t.Fatalf(
+			"unexpected error; expected %v, got %v",
+			gitconfig.InvalidExpiryError, _err,
+		)
+	}
+} // TestPropertyDuration()
@@ -0,0 +1,162 @@
+package gitconfig
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// _PREFIX is the installation prefix of git, used to expand "%(prefix)/"
+// paths; git installed under "/usr" reads its system configuration from
+// "/etc/gitconfig"
+const _PREFIX = "/usr"
+
+// Files returns the ordered list of configuration files git reads for the
+// given scope, following git's file discovery rules. Files that do not exist
+// are included in the list, but are ignored when configuration is loaded.
+// path identifies the repository for LocalScope and WorktreeScope, and if
+// path is "", the current working directory of the process will be used.
+//
+// The system configuration is "/etc/gitconfig", or the file named
+// by GIT_CONFIG_SYSTEM, and is not read if GIT_CONFIG_NOSYSTEM is true. The
+// global configuration is "$XDG_CONFIG_HOME/git/config" (defaulting to
+// "~/.config/git/config") followed by "~/.gitconfig", or the file named by
+// GIT_CONFIG_GLOBAL. The local configuration is the "config" file of the
+// repository, and the worktree configuration is the "config.worktree" file
+// of the working tree if the repository enables the
+// "extensions.worktreeConfig" extension, or the local configuration file
+// otherwise, as git treats the two as the same.
+//
+// If scope is LocalScope or WorktreeScope and path is not within a working
+// copy, MissingWorkingCopyError is returned. For other scopes, Files returns
+// InvalidScopeError.
+func Files(scope Scope, path string) ([]string, error) {
+	switch scope {
+	case SystemScope:
+		if _nosystem := boolean(os.Getenv("GIT_CONFIG_NOSYSTEM")); _nosystem != nil && *_nosystem {
+			return []string{}, nil
+		} else if _system, _ok := os.LookupEnv("GIT_CONFIG_SYSTEM"); _ok {
+			return []string{_system}, nil
+		}
+		return []string{"/etc/gitconfig"}, nil
+
+	case GlobalScope:
+		if _global, _ok := os.LookupEnv("GIT_CONFIG_GLOBAL"); _ok {
+			return []string{_global}, nil
+		}
+
+		_files := make([]string, 0, 2)
+		_home := os.Getenv("HOME")
+		_xdg := os.Getenv("XDG_CONFIG_HOME")
+		if _xdg != "" {
+			_files = append(_files, filepath.Join(_xdg, "git", "config"))
+		} else if _home != "" {
+			_files = append(_files, filepath.Join(_home, ".config", "git", "config"))
+		}
+		if _home != "" {
+			_files = append(_files, filepath.Join(_home, ".gitconfig"))
+		}
+		return _files, nil
+
+	case LocalScope, WorktreeScope:
+		_repository, _err := discover(path)
+		if _err != nil {
+			return nil, _err
+		}
+		_local := filepath.Join(_repository.commondir, "config")
+		if scope == LocalScope {
+			return []string{_local}, nil
+		}
+
+		// is the worktree configuration enabled?
+		//		- if not, git treats the worktree configuration as the local
+		//		  configuration
+		_config, _err := NewConfigFromFile(_local)
+		if _err != nil {
+			if os.IsNotExist(_err) {
+				return []string{_local}, nil
+			}
+			return nil, _err
+		}
+		_enabled := _config.Get("extensions.worktreeconfig")
+		if _enabled != nil {
+			if _bool, _err := _enabled.Bool(); _err == nil && _bool {
+				_worktree := filepath.Join(_repository.gitdir, "config.worktree")
+				return []string{_worktree}, nil
+			}
+		}
+		return []string{_local}, nil
+	}
+
+	return nil, InvalidScopeError
+} // Files()
+
+//
+// private functions
+//
+
+// discover returns the repository containing path, or
+// MissingWorkingCopyError if path is not within a working copy. If path is
+// "", the current working directory of the process will be used.
+func discover(path string) (*repository, error) {
+	if path == "" {
+		_cwd, _err := os.Getwd()
+		if _err != nil {
+			return nil, _err
+		}
+		path = _cwd
+	}
+
+	_repository, _err := newRepository(path)
+	if _err != nil {
+		return nil, _err
+	} else if _repository == nil {
+		return nil, MissingWorkingCopyError
+	}
+
+	return _repository, nil
+} // discover()
+
+// native returns the Config instance for the given scope, loaded natively
+// from the files given by Files, with includes resolved. path identifies the
+// repository for LocalScope and WorktreeScope, and is used to evaluate
+// conditional includes.
+func native(scope Scope, path string) (Config, error) {
+	_files, _err := Files(scope, path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	// determine all configuration files, to evaluate hasconfig: includes
+	//		- the repository is only required for conditional includes
+	_repository, _ := discover(path)
+	//		- the worktree files may be the local configuration file
+	_all := make([]string, 0)
+	_seen := make(map[string]bool)
+	for _, _scope := range []Scope{
+		SystemScope, GlobalScope, LocalScope, WorktreeScope,
+	} {
+		_scoped, _ := Files(_scope, path)
+		for _, _file := range _scoped {
+			if !_seen[_file] {
+				_seen[_file] = true
+				_all = append(_all, _file)
+			}
+		}
+	}
+
+	// load the configuration files
+	_loader := newLoader(scope, _repository, _all...)
+	_properties := make([]Property, 0)
+	for _, _file := range _files {
+		_loaded, _err := _loader.load(_file)
+		if _err != nil {
+			if os.IsNotExist(_err) {
+				continue
+			}
+			return nil, _err
+		}
+		_properties = append(_properties, _loaded...)
+	}
+
+	return NewConfig(_properties), nil
+} // native()
@@ -0,0 +1,154 @@
+package gitconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+// the environment variables used for configuration file discovery
+var _DISCOVERY = []string{
+	"GIT_CONFIG_NOSYSTEM",
+	"GIT_CONFIG_SYSTEM",
+	"GIT_CONFIG_GLOBAL",
+	"XDG_CONFIG_HOME",
+	"HOME",
+}
+
+func TestFiles(t *testing.T) {
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+	_dir, _ = filepath.EvalSymlinks(_dir)
+
+	// restore the environment once we're done
+	_original := make(map[string]*string)
+	for _, _name := range _DISCOVERY {
+		if _value, _ok := os.LookupEnv(_name); _ok {
+			_original[_name] = &_value
+		} else {
+			_original[_name] = nil
+		}
+		os.Unsetenv(_name)
+	}
+	defer func() {
+		for _name, _value := range _original {
+			if _value == nil {
+				os.Unsetenv(_name)
+			} else {
+				os.Setenv(_name, *_value)
+			}
+		}
+	}()
+
+	// create a repository with the worktree configuration enabled
+	_root := filepath.Join(_dir, "project")
+	_gitdir := filepath.Join(_root, ".git")
+	write(t, filepath.Join(_gitdir, "HEAD"), "ref: refs/heads/main\n")
+	write(t, filepath.Join(_gitdir, "config"), "[core]\n\tbare = false\n")
+
+	_home := filepath.Join(_dir, "home")
+	for _i, _test := range []struct {
+		environment map[string]string
+		scope       gitconfig.Scope
+		path        string
+		files       []string
+		err         error
+	}{
+		{nil, gitconfig.SystemScope, "", []string{"/etc/gitconfig"}, nil},
+		{
+			map[string]string{"GIT_CONFIG_SYSTEM": "/tmp/system"},
+			gitconfig.SystemScope, "", []string{"/tmp/system"}, nil,
+		},
+		{
+			map[string]string{
+				"GIT_CONFIG_SYSTEM":   "/tmp/system",
+				"GIT_CONFIG_NOSYSTEM": "true",
+			},
+			gitconfig.SystemScope, "", []string{}, nil,
+		},
+		{nil, gitconfig.GlobalScope, "", []string{}, nil},
+		{
+			map[string]string{"HOME": _home},
+			gitconfig.GlobalScope, "",
+			[]string{
+				filepath.Join(_home, ".config", "git", "config"),
+				filepath.Join(_home, ".gitconfig"),
+			},
+			nil,
+		},
+		{
+			map[string]string{"HOME": _home, "XDG_CONFIG_HOME": "/tmp/xdg"},
+			gitconfig.GlobalScope, "",
+			[]string{"/tmp/xdg/git/config", filepath.Join(_home, ".gitconfig")},
+			nil,
+		},
+		{
+			map[string]string{"HOME": _home, "GIT_CONFIG_GLOBAL": "/tmp/global"},
+			gitconfig.GlobalScope, "", []string{"/tmp/global"}, nil,
+		},
+		{
+			nil, gitconfig.LocalScope, _root,
+			[]string{filepath.Join(_gitdir, "config")}, nil,
+		},
+		{
+			nil, gitconfig.WorktreeScope, _root,
+			[]string{filepath.Join(_gitdir, "config")}, nil,
+		},
+		{nil, gitconfig.LocalScope, _dir, nil, gitconfig.MissingWorkingCopyError},
+		{nil, gitconfig.CommandScope, "", nil, gitconfig.InvalidScopeError},
+		{nil, gitconfig.FileScope, "", nil, gitconfig.InvalidScopeError},
+	} {
+		for _, _name := range _DISCOVERY {
+			os.Unsetenv(_name)
+			if _value, _ok := _test.environment[_name]; _ok {
+				os.Setenv(_name, _value)
+			}
+		}
+
+		_files, _err := gitconfig.Files(_test.scope, _test.path)
+		if _err != _test.err {
+			t.Fatalf(
+				"%d: unexpected error; expected %v, got %v",
+				_i, _test.err, _err,
+			)
+		} else if _err != nil {
+			continue
+		}
+		if len(_files) != len(_test.files) {
+			t.Fatalf(
+				"%d: unexpected files; expected %v, got %v",
+				_i, _test.files, _files,
+			)
+		}
+		for _j, _file := range _files {
+			if _file != _test.files[_j] {
+				t.Fatalf(
+					"%d: unexpected file; expected %q, got %q",
+					_i, _test.files[_j], _file,
+				)
+			}
+		}
+	}
+
+	// enable the worktree configuration
+	write(
+		t, filepath.Join(_gitdir, "config"),
+		"[extensions]\n\tworktreeConfig = true\n",
+	)
+	_files, _err := gitconfig.Files(gitconfig.WorktreeScope, _root)
+	if _err != nil {
+		t.Fatalf("unexpected error from Files(): %s", _err.Error())
+	} else if len(_files) != 1 ||
+		_files[0] != filepath.Join(_gitdir, "config.worktree") {
+		t.Fatalf("unexpected worktree files: %v", _files)
+	}
+} // TestFiles()
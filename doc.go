@@ -1,10 +1,14 @@
 /*
 Package gitconfig provides an interface to git configuration properties
-as returned by "git config --list". gitconfig provides access to local,
-global and system configuration, as well as the effective configuration
+as returned by "git config --list". gitconfig provides access to worktree,
+local, global and system configuration, as well as the effective configuration
 for the given git working copy. gitconfig attempts to use the locally installed
 "git" executable using https://github.com/denormal/go-gittools.
 
+gitconfig also provides a native parser for git configuration files, allowing
+configuration to be loaded with NewConfigFromFile and NewConfigFromReader
+where the "git" executable is not available.
+
 See https://git-scm.com/docs/git-config for more information.
 */
 package gitconfig
@@ -0,0 +1,632 @@
+package gitconfig
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var (
+	InvalidKeyError      = errors.New("invalid key")
+	MissingPropertyError = errors.New("property not found")
+	MissingSectionError  = errors.New("section not found")
+	MissingPathError     = errors.New("document has no path")
+	LockedFileError      = errors.New("configuration file is locked")
+	MultipleValuesError  = errors.New("property has multiple values")
+)
+
+// Document is the interface to a single git configuration file that may be
+// modified and written back, preserving the comments, blank lines, ordering
+// and indentation of the original file. Only the lines affected by a
+// modification are changed when the Document is written.
+type Document interface {
+	Writer
+
+	// Path returns the path of the configuration file represented by this
+	// Document, or the empty string if the Document was not read from a file.
+	Path() string
+
+	// Config returns the configuration properties currently defined by
+	// this Document.
+	Config() Config
+
+	// Bytes returns the current content of the configuration file.
+	Bytes() []byte
+
+	// WriteTo writes the current content of the configuration file to w.
+	WriteTo(w io.Writer) (int64, error)
+
+	// Save writes the current content of the configuration file to Path.
+	// If the Document has no path, Save returns MissingPathError. If the
+	// file is locked for writing by another process, such as git, Save
+	// returns LockedFileError.
+	Save() error
+}
+
+// fragment is a token of the document: a section header, an entry, or a
+// line (or part of a line) of comments or whitespace
+type fragment struct {
+	element *element // nil for comments and whitespace
+	raw     []byte
+}
+
+// block is a section of the document, including its header
+type block struct {
+	header    *fragment // nil for the content preceding the first section
+	fragments []*fragment
+}
+
+// document is the implementation of the Document interface
+type document struct {
+	path   string
+	blocks []*block
+}
+
+// NewDocumentFromReader returns the Document for the git configuration file
+// content read from r. The returned Document has no path, and so cannot be
+// saved with Save. If the content is not valid git configuration, a
+// *ParseError is returned.
+func NewDocumentFromReader(r io.Reader) (Document, error) {
+	_data, _err := ioutil.ReadAll(r)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return newDocument("", _data)
+} // NewDocumentFromReader()
+
+// NewDocumentFromFile returns the Document for the git configuration file at
+// path. If the file cannot be read, or it is not valid git configuration, an
+// error is returned.
+func NewDocumentFromFile(path string) (Document, error) {
+	_data, _err := ioutil.ReadFile(path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return newDocument(path, _data)
+} // NewDocumentFromFile()
+
+// Path returns the path of the configuration file represented by this
+// Document, or the empty string if the Document was not read from a file.
+func (d *document) Path() string { return d.path }
+
+// Scope returns the scope of the configuration modified by this Document.
+func (d *document) Scope() Scope { return FileScope }
+
+// Config returns the configuration properties currently defined by this
+// Document.
+func (d *document) Config() Config {
+	_properties := make([]Property, 0)
+	_line := 1
+	for _, _block := range d.blocks {
+		if _block.header != nil {
+			_line += bytes.Count(_block.header.raw, []byte("\n"))
+		}
+		for _, _fragment := range _block.fragments {
+			_element := _fragment.element
+			if _element != nil {
+				_property := newProperty(
+					_element.name(), _element.value, _element.novalue,
+					FileScope, d.path, _line,
+				)
+				_properties = append(_properties, _property)
+			}
+			_line += bytes.Count(_fragment.raw, []byte("\n"))
+		}
+	}
+
+	return NewConfig(_properties)
+} // Config()
+
+// Bytes returns the current content of the configuration file.
+func (d *document) Bytes() []byte {
+	_bytes := bytes.NewBuffer(nil)
+	d.WriteTo(_bytes)
+
+	return _bytes.Bytes()
+} // Bytes()
+
+// WriteTo writes the current content of the configuration file to w.
+func (d *document) WriteTo(w io.Writer) (int64, error) {
+	_total := int64(0)
+	for _, _block := range d.blocks {
+		_fragments := _block.fragments
+		if _block.header != nil {
+			_fragments = append([]*fragment{_block.header}, _fragments...)
+		}
+		for _, _fragment := range _fragments {
+			_n, _err := w.Write(_fragment.raw)
+			_total += int64(_n)
+			if _err != nil {
+				return _total, _err
+			}
+		}
+	}
+
+	return _total, nil
+} // WriteTo()
+
+// Save writes the current content of the configuration file to Path. If the
+// Document has no path, Save returns MissingPathError, and if the file is
+// locked for writing, Save returns LockedFileError.
+func (d *document) Save() error {
+	if d.path == "" {
+		return MissingPathError
+	}
+
+	// retain the permissions of an existing file
+	_mode := os.FileMode(0644)
+	_info, _err := os.Stat(d.path)
+	if _err == nil {
+		_mode = _info.Mode().Perm()
+	} else if !os.IsNotExist(_err) {
+		return _err
+	}
+
+	// write to a lock file before renaming it into place, as git does
+	//		- an existing lock file is held by another process, so must
+	//		  not be touched
+	_lock := d.path + ".lock"
+	_file, _err := os.OpenFile(_lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, _mode)
+	if _err != nil {
+		if os.IsExist(_err) {
+			return LockedFileError
+		}
+		return _err
+	}
+	_, _err = _file.Write(d.Bytes())
+	if _close := _file.Close(); _err == nil {
+		_err = _close
+	}
+	if _err == nil {
+		_err = os.Rename(_lock, d.path)
+	}
+	if _err != nil {
+		os.Remove(_lock)
+	}
+
+	return _err
+} // Save()
+
+// Set sets the property with the given name to value, replacing all
+// existing values of the property. The last existing value is updated in
+// place, with all other values removed.
+func (d *document) Set(name, value string) error {
+	_section, _subsection, _variable, _err := split(name)
+	if _err != nil {
+		return _err
+	}
+
+	// if the property is not present, then add it
+	_matches := d.entries(_section, _subsection, _variable)
+	if len(_matches) == 0 {
+		return d.Add(name, value)
+	}
+
+	// replace the last value, and remove the others
+	_last := _matches[len(_matches)-1]
+	_last.element.value = value
+	_last.element.novalue = false
+	_last.raw = entry(indentation(_last.raw), _variable, value)
+	d.remove(_matches[:len(_matches)-1])
+
+	return nil
+} // Set()
+
+// Add adds value to the property with the given name, retaining any existing
+// values of the property. The new value is added to the last section of the
+// document with the matching name, or to a new section at the end of the
+// document if no such section exists.
+func (d *document) Add(name, value string) error {
+	_section, _subsection, _variable, _err := split(name)
+	if _err != nil {
+		return _err
+	}
+
+	// find the last matching section
+	var _block *block
+	for _, _b := range d.blocks {
+		if _b.header != nil && _b.header.element.is(_section, _subsection) {
+			_block = _b
+		}
+	}
+
+	// if there's no matching section, append a new section
+	if _block == nil {
+		d.terminate(d.blocks[len(d.blocks)-1])
+		_block = &block{
+			header: &fragment{
+				element: &element{
+					token:      _SECTION,
+					section:    strings.ToLower(_section),
+					subsection: _subsection,
+				},
+				raw: header("", _section, _subsection),
+			},
+			fragments: []*fragment{{raw: []byte("\n")}},
+		}
+		d.blocks = append(d.blocks, _block)
+	}
+
+	// the new entry follows the last entry in the section, or the end of
+	// the section header line if the section has no entries
+	_index := -1
+	_indent := []byte("\t")
+	for _i, _fragment := range _block.fragments {
+		if _fragment.element != nil {
+			_index = _i
+			_indent = indentation(_fragment.raw)
+		} else if _index == -1 && bytes.HasSuffix(_fragment.raw, []byte("\n")) {
+			_index = _i
+		}
+	}
+	if _index == -1 {
+		_block.fragments = append(_block.fragments, &fragment{})
+		_index = len(_block.fragments) - 1
+	}
+	_previous := _block.fragments[_index]
+	if !bytes.HasSuffix(_previous.raw, []byte("\n")) {
+		_previous.raw = append(_previous.raw, '\n')
+	}
+
+	// insert the new entry
+	_fragment := &fragment{
+		element: &element{
+			token:      _ENTRY,
+			section:    strings.ToLower(_section),
+			subsection: _subsection,
+			variable:   strings.ToLower(_variable),
+			value:      value,
+		},
+		raw: entry(_indent, _variable, value),
+	}
+	_fragments := append([]*fragment{}, _block.fragments[:_index+1]...)
+	_fragments = append(_fragments, _fragment)
+	_block.fragments = append(_fragments, _block.fragments[_index+1:]...)
+
+	return nil
+} // Add()
+
+// Unset removes the property with the given name. If the property has
+// multiple values, Unset returns MultipleValuesError, and if the property
+// is not set, Unset returns MissingPropertyError.
+func (d *document) Unset(name string) error {
+	_section, _subsection, _variable, _err := split(name)
+	if _err != nil {
+		return _err
+	}
+
+	_matches := d.entries(_section, _subsection, _variable)
+	switch len(_matches) {
+	case 0:
+		return MissingPropertyError
+	case 1:
+		d.remove(_matches)
+		return nil
+	}
+
+	return MultipleValuesError
+} // Unset()
+
+// UnsetAll removes all values of the property with the given name. If the
+// property is not set, UnsetAll returns MissingPropertyError.
+func (d *document) UnsetAll(name string) error {
+	_section, _subsection, _variable, _err := split(name)
+	if _err != nil {
+		return _err
+	}
+
+	_matches := d.entries(_section, _subsection, _variable)
+	if len(_matches) == 0 {
+		return MissingPropertyError
+	}
+	d.remove(_matches)
+
+	return nil
+} // UnsetAll()
+
+// RenameSection renames the section old to new, where sections are named
+// either "section" or "section.subsection". If no section named old exists,
+// RenameSection returns MissingSectionError.
+func (d *document) RenameSection(old, new string) error {
+	_section, _subsection, _err := splitSection(old)
+	if _err != nil {
+		return _err
+	}
+	_newSection, _newSubsection, _err := splitSection(new)
+	if _err != nil {
+		return _err
+	}
+
+	// update the header of each matching section, as well as the entries
+	// of the section
+	_found := false
+	for _, _block := range d.blocks {
+		_header := _block.header
+		if _header == nil || !_header.element.is(_section, _subsection) {
+			continue
+		}
+		_found = true
+
+		_header.raw = header(
+			string(indentation(_header.raw)), _newSection, _newSubsection,
+		)
+		_header.element.section = strings.ToLower(_newSection)
+		_header.element.subsection = _newSubsection
+		for _, _fragment := range _block.fragments {
+			if _fragment.element != nil {
+				_fragment.element.section = _header.element.section
+				_fragment.element.subsection = _newSubsection
+			}
+		}
+	}
+	if !_found {
+		return MissingSectionError
+	}
+
+	return nil
+} // RenameSection()
+
+// RemoveSection removes the section with the given name, including all of
+// its properties and comments. If no section with the given name exists,
+// RemoveSection returns MissingSectionError.
+func (d *document) RemoveSection(name string) error {
+	_section, _subsection, _err := splitSection(name)
+	if _err != nil {
+		return _err
+	}
+
+	_blocks := make([]*block, 0, len(d.blocks))
+	for _, _block := range d.blocks {
+		_header := _block.header
+		if _header == nil || !_header.element.is(_section, _subsection) {
+			_blocks = append(_blocks, _block)
+		}
+	}
+	if len(_blocks) == len(d.blocks) {
+		return MissingSectionError
+	}
+	d.blocks = _blocks
+
+	return nil
+} // RemoveSection()
+
+//
+// private methods
+//
+
+// entries returns the list of entry fragments for the given property.
+func (d *document) entries(section, subsection, variable string) []*fragment {
+	_entries := make([]*fragment, 0)
+	for _, _block := range d.blocks {
+		for _, _fragment := range _block.fragments {
+			_element := _fragment.element
+			if _element != nil &&
+				_element.is(section, subsection) &&
+				strings.EqualFold(_element.variable, variable) {
+				_entries = append(_entries, _fragment)
+			}
+		}
+	}
+
+	return _entries
+} // entries()
+
+// remove removes the given entry fragments from the document. Sections
+// left with no entries and no comments are removed entirely.
+func (d *document) remove(fragments []*fragment) {
+	_remove := make(map[*fragment]bool)
+	for _, _fragment := range fragments {
+		_remove[_fragment] = true
+	}
+
+	_blocks := make([]*block, 0, len(d.blocks))
+	for _, _block := range d.blocks {
+		_fragments := make([]*fragment, 0, len(_block.fragments))
+		_empty := true
+		for _, _fragment := range _block.fragments {
+			if _remove[_fragment] {
+				continue
+			}
+			_fragments = append(_fragments, _fragment)
+			if _fragment.element != nil ||
+				len(bytes.TrimSpace(_fragment.raw)) != 0 {
+				_empty = false
+			}
+		}
+
+		// if we have removed everything from a section, remove the section
+		if _empty && _block.header != nil &&
+			len(_fragments) != len(_block.fragments) {
+			continue
+		}
+		_block.fragments = _fragments
+		_blocks = append(_blocks, _block)
+	}
+	d.blocks = _blocks
+} // remove()
+
+// terminate ensures the last fragment of the given block ends with a
+// newline.
+func (d *document) terminate(b *block) {
+	_last := b.header
+	if len(b.fragments) != 0 {
+		_last = b.fragments[len(b.fragments)-1]
+	}
+	if _last != nil && len(_last.raw) != 0 &&
+		!bytes.HasSuffix(_last.raw, []byte("\n")) {
+		_last.raw = append(_last.raw, '\n')
+	}
+} // terminate()
+
+// is returns true if the element belongs to the given section and
+// subsection. Section names are compared case-insensitively, while
+// subsection names are case-sensitive.
+func (e *element) is(section, subsection string) bool {
+	return strings.EqualFold(e.section, section) && e.subsection == subsection
+} // is()
+
+//
+// private functions
+//
+
+// newDocument returns the document representing the configuration data,
+// where path is the path of the configuration file.
+func newDocument(path string, data []byte) (Document, error) {
+	_document := &document{path: path, blocks: []*block{{}}}
+	_block := _document.blocks[0]
+	_offset := 0
+
+	// gap adds the comments and whitespace up to end to the current block,
+	// with one fragment per line
+	_gap := func(end int) {
+		for _offset < end {
+			_next := bytes.IndexByte(data[_offset:end], '\n')
+			if _next == -1 {
+				_next = end
+			} else {
+				_next += _offset + 1
+			}
+			_raw := append([]byte{}, data[_offset:_next]...)
+			_block.fragments = append(_block.fragments, &fragment{raw: _raw})
+			_offset = _next
+		}
+	} // _gap()
+
+	_err := newParser(path, data).parse(func(e *element) error {
+		// include any indentation in the element
+		_start := e.start
+		for _start > _offset && (data[_start-1] == ' ' || data[_start-1] == '\t') {
+			_start--
+		}
+		if _start > 0 && data[_start-1] != '\n' {
+			_start = e.start
+		}
+		_gap(_start)
+
+		_fragment := &fragment{e, append([]byte{}, data[_start:e.end]...)}
+		if e.token == _SECTION {
+			_block = &block{header: _fragment}
+			_document.blocks = append(_document.blocks, _block)
+		} else {
+			_block.fragments = append(_block.fragments, _fragment)
+		}
+		_offset = e.end
+
+		return nil
+	})
+	if _err != nil {
+		return nil, _err
+	}
+	_gap(len(data))
+
+	return _document, nil
+} // newDocument()
+
+// split splits the property name into its section, subsection and variable
+// names. If the name is not a valid property name, InvalidKeyError is
+// returned.
+func split(name string) (string, string, string, error) {
+	_section, _subsection, _variable, _ok := fields(name)
+	if !_ok || !valid(_section, true) || !valid(_variable, false) {
+		return "", "", "", InvalidKeyError
+	} else if _subsection == nil {
+		return _section, "", _variable, nil
+	}
+
+	return _section, *_subsection, _variable, nil
+} // split()
+
+// splitSection splits the section name into its section and subsection
+// names. If the name is not a valid section name, InvalidKeyError is
+// returned.
+func splitSection(name string) (string, string, error) {
+	_parts := strings.SplitN(name, ".", 2)
+	if !valid(_parts[0], true) {
+		return "", "", InvalidKeyError
+	} else if len(_parts) == 1 {
+		return _parts[0], "", nil
+	}
+
+	return _parts[0], _parts[1], nil
+} // splitSection()
+
+// valid returns true if name is a valid section name (if section is true)
+// or variable name. Variable names must start with a letter.
+func valid(name string, section bool) bool {
+	if name == "" || (!section && !isalpha(name[0])) {
+		return false
+	}
+	for _i := 0; _i < len(name); _i++ {
+		if !iskeychar(name[_i]) {
+			return false
+		}
+	}
+
+	return true
+} // valid()
+
+// indentation returns the leading whitespace of the fragment content raw.
+func indentation(raw []byte) []byte {
+	_end := 0
+	for _end < len(raw) && (raw[_end] == ' ' || raw[_end] == '\t') {
+		_end++
+	}
+
+	return append([]byte{}, raw[:_end]...)
+} // indentation()
+
+// header returns the content of a section header for the given section and
+// subsection names, prefixed with indent.
+func header(indent, section, subsection string) []byte {
+	if subsection == "" {
+		return []byte(indent + "[" + section + "]")
+	}
+
+	_replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return []byte(
+		indent + "[" + section + " \"" + _replacer.Replace(subsection) + "\"]",
+	)
+} // header()
+
+// entry returns the content of an entry line for the given variable name
+// and value, prefixed with indent.
+func entry(indent []byte, variable, value string) []byte {
+	_entry := bytes.NewBuffer(append([]byte{}, indent...))
+	_entry.WriteString(variable + " = " + quote(value) + "\n")
+
+	return _entry.Bytes()
+} // entry()
+
+// quote returns the value escaped and quoted, as necessary, for writing to a
+// git configuration file, so that it is parsed as the original value. Values
+// are quoted to keep leading or trailing whitespace, which is otherwise
+// stripped, "#" and ";" characters, which otherwise begin a comment, and
+// "\r", "\v" and "\f" characters, which are otherwise read as spaces. Tabs,
+// newlines, backspaces, quotes and backslashes are escaped.
+func quote(value string) string {
+	_quote := strings.HasPrefix(value, " ") ||
+		strings.HasSuffix(value, " ") ||
+		strings.ContainsAny(value, ";#\r\v\f")
+
+	_replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\t", `\t`,
+		"\b", `\b`,
+	)
+	_value := _replacer.Replace(value)
+	if _quote {
+		return `"` + _value + `"`
+	}
+
+	return _value
+} // quote()
+
+// ensure document conforms to the Document interface
+var _ Document = &document{}
@@ -0,0 +1,368 @@
+package gitconfig_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+type doctest struct {
+	edit     func(d gitconfig.Document) error
+	expected string
+}
+
+var (
+	// define the original document content for the document tests
+	_DOCUMENT = "" +
+		"# user configuration\n" +
+		"[user]\n" +
+		"    name = A. N. Other ; the user\n" +
+		"\n" +
+		"; remotes\n" +
+		"[remote \"origin\"]\n" +
+		"\turl = https://example.com/x.git\n" +
+		"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+		"\tfetch = +refs/tags/*:refs/tags/*\n" +
+		"[core]\n" +
+		"\tbare"
+
+	// define the document tests
+	_DOCUMENTS = []doctest{
+		{
+			func(d gitconfig.Document) error {
+				return d.Set("user.name", "Someone Else")
+			},
+			"" +
+				"# user configuration\n" +
+				"[user]\n" +
+				"    name = Someone Else\n" +
+				"\n" +
+				"; remotes\n" +
+				"[remote \"origin\"]\n" +
+				"\turl = https://example.com/x.git\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+				"\tfetch = +refs/tags/*:refs/tags/*\n" +
+				"[core]\n" +
+				"\tbare",
+		},
+		{
+			func(d gitconfig.Document) error {
+				return d.Set("User.Email", " me@example.com")
+			},
+			"" +
+				"# user configuration\n" +
+				"[user]\n" +
+				"    name = A. N. Other ; the user\n" +
+				"    Email = \" me@example.com\"\n" +
+				"\n" +
+				"; remotes\n" +
+				"[remote \"origin\"]\n" +
+				"\turl = https://example.com/x.git\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+				"\tfetch = +refs/tags/*:refs/tags/*\n" +
+				"[core]\n" +
+				"\tbare",
+		},
+		{
+			func(d gitconfig.Document) error {
+				return d.Set("remote.origin.fetch", "+refs/heads/main:refs/remotes/origin/main")
+			},
+			"" +
+				"# user configuration\n" +
+				"[user]\n" +
+				"    name = A. N. Other ; the user\n" +
+				"\n" +
+				"; remotes\n" +
+				"[remote \"origin\"]\n" +
+				"\turl = https://example.com/x.git\n" +
+				"\tfetch = +refs/heads/main:refs/remotes/origin/main\n" +
+				"[core]\n" +
+				"\tbare",
+		},
+		{
+			func(d gitconfig.Document) error {
+				return d.Add("core.editor", "vim")
+			},
+			"" +
+				"# user configuration\n" +
+				"[user]\n" +
+				"    name = A. N. Other ; the user\n" +
+				"\n" +
+				"; remotes\n" +
+				"[remote \"origin\"]\n" +
+				"\turl = https://example.com/x.git\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+				"\tfetch = +refs/tags/*:refs/tags/*\n" +
+				"[core]\n" +
+				"\tbare\n" +
+				"\teditor = vim\n",
+		},
+		{
+			func(d gitconfig.Document) error {
+				return d.Add("remote.Upstream.url", "a\"b\\c\td#e\n")
+			},
+			_DOCUMENT + "\n" +
+				"[remote \"Upstream\"]\n" +
+				"\turl = \"a\\\"b\\\\c\\td#e\\n\"\n",
+		},
+		{
+			func(d gitconfig.Document) error {
+				return d.Unset("user.name")
+			},
+			"" +
+				"# user configuration\n" +
+				"[user]\n" +
+				"\n" +
+				"; remotes\n" +
+				"[remote \"origin\"]\n" +
+				"\turl = https://example.com/x.git\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+				"\tfetch = +refs/tags/*:refs/tags/*\n" +
+				"[core]\n" +
+				"\tbare",
+		},
+		{
+			func(d gitconfig.Document) error {
+				return d.UnsetAll("remote.origin.fetch")
+			},
+			"" +
+				"# user configuration\n" +
+				"[user]\n" +
+				"    name = A. N. Other ; the user\n" +
+				"\n" +
+				"; remotes\n" +
+				"[remote \"origin\"]\n" +
+				"\turl = https://example.com/x.git\n" +
+				"[core]\n" +
+				"\tbare",
+		},
+		{
+			func(d gitconfig.Document) error {
+				return d.RenameSection("remote.origin", "remote.upstream")
+			},
+			"" +
+				"# user configuration\n" +
+				"[user]\n" +
+				"    name = A. N. Other ; the user\n" +
+				"\n" +
+				"; remotes\n" +
+				"[remote \"upstream\"]\n" +
+				"\turl = https://example.com/x.git\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+				"\tfetch = +refs/tags/*:refs/tags/*\n" +
+				"[core]\n" +
+				"\tbare",
+		},
+		{
+			func(d gitconfig.Document) error {
+				return d.RemoveSection("remote.origin")
+			},
+			"" +
+				"# user configuration\n" +
+				"[user]\n" +
+				"    name = A. N. Other ; the user\n" +
+				"\n" +
+				"; remotes\n" +
+				"[core]\n" +
+				"\tbare",
+		},
+	}
+
+	// define the document errors, mapping an edit to the expected error
+	_DOCUMENT_ERRORS = map[error]func(d gitconfig.Document) error{
+		gitconfig.InvalidKeyError: func(d gitconfig.Document) error {
+			return d.Set("name", "value")
+		},
+		gitconfig.MissingPropertyError: func(d gitconfig.Document) error {
+			return d.UnsetAll("user.email")
+		},
+		gitconfig.MultipleValuesError: func(d gitconfig.Document) error {
+			return d.Unset("remote.origin.fetch")
+		},
+		gitconfig.MissingSectionError: func(d gitconfig.Document) error {
+			return d.RemoveSection("remote.Origin")
+		},
+		gitconfig.MissingPathError: func(d gitconfig.Document) error {
+			return d.Save()
+		},
+	}
+)
+
+func TestDocument(t *testing.T) {
+	// ensure the document is reproduced byte-identical
+	_document := document(t)
+	if string(_document.Bytes()) != _DOCUMENT {
+		t.Fatalf(
+			"unexpected document content; expected %q, got %q",
+			_DOCUMENT, string(_document.Bytes()),
+		)
+	}
+
+	// ensure edits only change the affected lines
+	for _i, _test := range _DOCUMENTS {
+		_document := document(t)
+		if _err := _test.edit(_document); _err != nil {
+			t.Fatalf("%d: unexpected edit error: %s", _i, _err.Error())
+		}
+		_got := string(_document.Bytes())
+		if _got != _test.expected {
+			t.Fatalf(
+				"%d: unexpected document content; expected %q, got %q",
+				_i, _test.expected, _got,
+			)
+		}
+
+		// ensure the configuration matches the document content
+		_config, _err := gitconfig.NewConfigFromReader(strings.NewReader(_got))
+		if _err != nil {
+			t.Fatalf("%d: unable to parse document: %s", _i, _err.Error())
+		} else if _config.String() != _document.Config().String() {
+			t.Fatalf(
+				"%d: unexpected configuration; expected %q, got %q",
+				_i, _config.String(), _document.Config().String(),
+			)
+		}
+	}
+
+	// ensure line numbers reflect the edited document
+	_document = document(t)
+	if _err := _document.Unset("user.name"); _err != nil {
+		t.Fatalf("unexpected error from Unset(): %s", _err.Error())
+	}
+	_url := _document.Config().Get("remote.origin.url")
+	if _url.Line() != 6 {
+		t.Fatalf("unexpected line; expected %d, got %d", 6, _url.Line())
+	} else if _url.Scope() != gitconfig.FileScope {
+		t.Fatalf(
+			"unexpected scope; expected %s, got %s",
+			gitconfig.FileScope, _url.Scope(),
+		)
+	}
+
+	// ensure setting a property without a value gives it a value
+	_document = document(t)
+	if _err := _document.Set("core.bare", "false"); _err != nil {
+		t.Fatalf("unexpected error from Set(): %s", _err.Error())
+	}
+	_bare := _document.Config().Get("core.bare")
+	if !_bare.HasValue() {
+		t.Fatal("unexpected property without value; expected value")
+	} else if _bool, _err := _bare.Bool(); _err != nil {
+		t.Fatalf("unexpected error from Bool(): %s", _err.Error())
+	} else if _bool {
+		t.Fatal("unexpected boolean; expected false, got true")
+	}
+
+	// ensure errors are reported
+	for _expected, _edit := range _DOCUMENT_ERRORS {
+		_err := _edit(document(t))
+		if _err != _expected {
+			t.Fatalf(
+				"unexpected document error; expected %q, got %v",
+				_expected, _err,
+			)
+		}
+	}
+} // TestDocument()
+
+func TestDocumentSave(t *testing.T) {
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+
+	_path := filepath.Join(_dir, "config")
+	_err = ioutil.WriteFile(_path, []byte(_DOCUMENT), 0600)
+	if _err != nil {
+		t.Fatalf("unable to write configuration file: %s", _err.Error())
+	}
+
+	// load, edit and save the document
+	_document, _err := gitconfig.NewDocumentFromFile(_path)
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewDocumentFromFile(): %s",
+			_err.Error(),
+		)
+	} else if _document.Path() != _path {
+		t.Fatalf(
+			"unexpected path; expected %q, got %q",
+			_path, _document.Path(),
+		)
+	}
+	if _err = _document.Add("core.editor", "vim"); _err != nil {
+		t.Fatalf("unexpected error from Add(): %s", _err.Error())
+	} else if _err = _document.Save(); _err != nil {
+		t.Fatalf("unexpected error from Save(): %s", _err.Error())
+	}
+
+	// ensure the file has been written, retaining its permissions
+	_data, _err := ioutil.ReadFile(_path)
+	if _err != nil {
+		t.Fatalf("unable to read configuration file: %s", _err.Error())
+	} else if !bytes.Equal(_data, _document.Bytes()) {
+		t.Fatalf(
+			"unexpected file content; expected %q, got %q",
+			string(_document.Bytes()), string(_data),
+		)
+	}
+	_info, _err := os.Stat(_path)
+	if _err != nil {
+		t.Fatalf("unable to stat configuration file: %s", _err.Error())
+	} else if _info.Mode().Perm() != 0600 {
+		t.Fatalf(
+			"unexpected file mode; expected %v, got %v",
+			os.FileMode(0600), _info.Mode().Perm(),
+		)
+	}
+
+	// ensure an existing lock file is left untouched
+	_lock := _path + ".lock"
+	_err = ioutil.WriteFile(_lock, []byte("locked"), 0600)
+	if _err != nil {
+		t.Fatalf("unable to write lock file: %s", _err.Error())
+	}
+	if _err = _document.Add("core.pager", "less"); _err != nil {
+		t.Fatalf("unexpected error from Add(): %s", _err.Error())
+	} else if _err = _document.Save(); _err != gitconfig.LockedFileError {
+		t.Fatalf("expected LockedFileError from Save(), got %v", _err)
+	}
+	if _data, _err = ioutil.ReadFile(_lock); _err != nil {
+		t.Fatalf("unable to read lock file: %s", _err.Error())
+	} else if string(_data) != "locked" {
+		t.Fatalf("unexpected lock file content: %q", string(_data))
+	}
+	if _data, _err = ioutil.ReadFile(_path); _err != nil {
+		t.Fatalf("unable to read configuration file: %s", _err.Error())
+	} else if bytes.Contains(_data, []byte("pager")) {
+		t.Fatalf("unexpected file content: %q", string(_data))
+	}
+} // TestDocumentSave()
+
+//
+// helper functions
+//
+
+// document returns the Document for the test document content.
+func document(t *testing.T) gitconfig.Document {
+	_document, _err := gitconfig.NewDocumentFromReader(
+		strings.NewReader(_DOCUMENT),
+	)
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewDocumentFromReader(): %s",
+			_err.Error(),
+		)
+	}
+
+	return _document
+} // document()
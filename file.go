@@ -0,0 +1,96 @@
+package gitconfig
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// NewConfigFromReader returns the Config instance for the git configuration
+// file content read from r. The content is parsed natively, without using
+// the "git" executable. If the content is not valid git configuration, a
+// *ParseError is returned.
+func NewConfigFromReader(r io.Reader) (Config, error) {
+	_data, _err := ioutil.ReadAll(r)
+	if _err != nil {
+		return nil, _err
+	}
+
+	_properties, _err := parse(UnknownScope, "", _data)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return NewConfig(_properties), nil
+} // NewConfigFromReader()
+
+// NewConfigFromFile returns the Config instance for the git configuration
+// file at path. The file is parsed natively, without using the "git"
+// executable. If the file cannot be read, or it is not valid git
+// configuration, an error is returned.
+func NewConfigFromFile(path string) (Config, error) {
+	_data, _err := ioutil.ReadFile(path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	_properties, _err := parse(FileScope, path, _data)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return NewConfig(_properties), nil
+} // NewConfigFromFile()
+
+// NewConfigFromFileWithIncludes returns the Config instance for the git
+// configuration file at path, as for NewConfigFromFile, with the
+// include.path and includeIf.<condition>.path directives resolved. Included
+// files are loaded relative to the including file, and missing included
+// files are ignored. The gitdir: and onbranch: conditions are evaluated
+// against the git working copy containing root, and are not satisfied if
+// root is "" or is not within a working copy.
+//
+// If the includes are nested more deeply than git allows, IncludeDepthError
+// is returned, while IncludeCycleError is returned if a file includes
+// itself.
+func NewConfigFromFileWithIncludes(path, root string) (Config, error) {
+	var _repository *repository
+	if root != "" {
+		var _err error
+		_repository, _err = newRepository(root)
+		if _err != nil {
+			return nil, _err
+		}
+	}
+
+	_properties, _err := newLoader(FileScope, _repository, path).load(path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return NewConfig(_properties), nil
+} // NewConfigFromFileWithIncludes()
+
+//
+// private functions
+//
+
+// parse returns the list of configuration properties defined by the
+// configuration data, in the order they are defined. file is the path of
+// the configuration file, and scope is the scope of its configuration.
+func parse(scope Scope, file string, data []byte) ([]Property, error) {
+	_properties := make([]Property, 0)
+	_err := newParser(file, data).parse(func(e *element) error {
+		if e.token == _ENTRY {
+			_property := newProperty(
+				e.name(), e.value, e.novalue, scope, file, e.line,
+			)
+			_properties = append(_properties, _property)
+		}
+		return nil
+	})
+	if _err != nil {
+		return nil, _err
+	}
+
+	return _properties, nil
+} // parse()
@@ -0,0 +1,87 @@
+package gitconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+func TestNewConfigFromFile(t *testing.T) {
+	// create a temporary configuration file
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+
+	_path := filepath.Join(_dir, "config")
+	_content := "[user]\n\tname = A. N. Other\n\temail = other@example.com\n"
+	_err = ioutil.WriteFile(_path, []byte(_content), 0644)
+	if _err != nil {
+		t.Fatalf("unable to write configuration file: %s", _err.Error())
+	}
+
+	// ensure the file is parsed as expected
+	_config, _err := gitconfig.NewConfigFromFile(_path)
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewConfigFromFile(): %s",
+			_err.Error(),
+		)
+	} else if _config == nil {
+		t.Fatal("unexpected nil Config; expected instance")
+	} else if _config.String() !=
+		"user.email=other@example.com\nuser.name=A. N. Other\n" {
+		t.Fatalf("unexpected configuration: %q", _config.String())
+	}
+
+	// ensure the origin of each property is recorded
+	for _name, _line := range map[string]int{"user.name": 2, "user.email": 3} {
+		_property := _config.Get(_name)
+		if _property.Scope() != gitconfig.FileScope {
+			t.Fatalf(
+				"%q: unexpected scope; expected %s, got %s",
+				_name, gitconfig.FileScope, _property.Scope(),
+			)
+		} else if _property.File() != _path {
+			t.Fatalf(
+				"%q: unexpected file; expected %q, got %q",
+				_name, _path, _property.File(),
+			)
+		} else if _property.Line() != _line {
+			t.Fatalf(
+				"%q: unexpected line; expected %d, got %d",
+				_name, _line, _property.Line(),
+			)
+		}
+	}
+
+	// ensure parse errors report the file
+	_err = ioutil.WriteFile(_path, []byte("[user\n"), 0644)
+	if _err != nil {
+		t.Fatalf("unable to write configuration file: %s", _err.Error())
+	}
+	_, _err = gitconfig.NewConfigFromFile(_path)
+	if _err == nil {
+		t.Fatal("expected error from NewConfigFromFile(); none found")
+	} else if _error, _ok := _err.(*gitconfig.ParseError); !_ok {
+		t.Fatalf("unexpected error type; expected %T, got %T", _error, _err)
+	} else if _error.File != _path {
+		t.Fatalf(
+			"unexpected error file; expected %q, got %q",
+			_path, _error.File,
+		)
+	}
+
+	// ensure missing files are reported
+	_, _err = gitconfig.NewConfigFromFile(filepath.Join(_dir, "missing"))
+	if _err == nil {
+		t.Fatal("expected error from NewConfigFromFile(); none found")
+	}
+} // TestNewConfigFromFile()
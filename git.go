@@ -1,31 +1,52 @@
 package gitconfig
 
 import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/denormal/go-gittools"
 )
 
-var _CONFIG = []string{"config", "--list"}
+var (
+	InvalidOutputError = errors.New("invalid git config output")
+)
+
+var _CONFIG = []string{"config", "--list", "-z", "--show-origin", "--includes"}
 
 // NewLocalConfig returns the Config instance for the local git configuration,
 // for the repository represented by path. If there is a problem extracting
 // this configuration, and Error is returned. If path is "", the current
 // working directory of the process will be used.
 func NewLocalConfig(path string) (Config, error) {
-	return gitconfig(path, "--local")
+	return gitconfig(path, LocalScope)
 } // getLocal()
 
+// NewWorktreeConfig returns the Config instance for the worktree git
+// configuration of the working tree represented by path, as stored in the
+// "config.worktree" file of the working tree's git directory. If the
+// repository does not enable the "extensions.worktreeConfig" extension, git
+// treats the worktree configuration as the local configuration. If there is a
+// problem extracting this configuration, and Error is returned. If path is "",
+// the current working directory of the process will be used.
+func NewWorktreeConfig(path string) (Config, error) {
+	return gitconfig(path, WorktreeScope)
+} // NewWorktreeConfig()
+
 // NewSystemConfig returns the Config instance for the system git configuration.
-// If there is a problem extracting this configuration, and Error is returned.
+// If none of the system configuration files exist, the Config is empty. If
+// there is a problem extracting this configuration, and Error is returned.
 func NewSystemConfig() (Config, error) {
-	return gitconfig("", "--system")
+	return gitconfig("", SystemScope)
 } // NewSystemConfig()
 
 // NewGlobalConfig returns the Config instance for the global git configuration.
-// If there is a problem extracting this configuration, and Error is returned.
+// If none of the global configuration files exist, the Config is empty. If
+// there is a problem extracting this configuration, and Error is returned.
 func NewGlobalConfig() (Config, error) {
-	return gitconfig("", "--global")
+	return gitconfig("", GlobalScope)
 } // NewGlobalConfig()
 
 //
@@ -33,12 +54,29 @@ func NewGlobalConfig() (Config, error) {
 //
 
 // gitconfig returns the list of configuration properties for the "git config"
-// command executed in the given path with the supplied flag. An Error is
-// returned if there is a problem executing git, or parsing a property.
-func gitconfig(path, flag string) (Config, error) {
-	// add the flag to the argument list
-	_args := _CONFIG
-	_args = append(_args, flag)
+// command executed in the given path for the given scope, with includes
+// resolved. If git is not installed, the configuration files of the scope
+// are loaded natively. An Error is returned if there is a problem executing
+// git, or parsing a property.
+func gitconfig(path string, scope Scope) (Config, error) {
+	if !gittools.HasGit() {
+		return native(scope, path)
+	}
+
+	// git fails if none of the system or global configuration files exist,
+	// so treat them as empty, as is done when loading them natively
+	if scope == SystemScope || scope == GlobalScope {
+		_exists, _err := exists(scope, path)
+		if _err != nil {
+			return nil, _err
+		} else if !_exists {
+			return NewConfig(nil), nil
+		}
+	}
+
+	// add the scope flag to the argument list
+	_args := append([]string{}, _CONFIG...)
+	_args = append(_args, "--"+scope.String())
 
 	// attempt to execute the "git config" command
 	_output, _err := gittools.RunInPath(path, _args...)
@@ -46,26 +84,93 @@ func gitconfig(path, flag string) (Config, error) {
 		return nil, _err
 	}
 
-	// parse the configuration output into properties
-	_lines := strings.Split(string(_output), "\n")
-	_properties := make([]Property, 0, len(_lines))
-	for _, _line := range _lines {
-		// remove possible "\r" line ending
-		_line = strings.TrimSpace(_line)
-		_parts := strings.SplitN(_line, "=", 2)
-		switch len(_parts) {
-		case 1: // we have a name only
-			if _parts[0] != "" {
-				_property := NewProperty(_parts[0], "")
-				_properties = append(_properties, _property)
-			}
-		case 2: // we have a name and property
-			if _parts[0] != "" {
-				_property := NewProperty(_parts[0], _parts[1])
-				_properties = append(_properties, _property)
-			}
+	// relative file origins are reported relative to the root of the
+	// working copy
+	_dir := path
+	if scope == LocalScope || scope == WorktreeScope {
+		_root, _err := gittools.WorkingCopy(path)
+		if _err == nil {
+			_dir = _root
 		}
 	}
 
+	// parse the configuration output into properties
+	_properties, _err := records(_output, scope, _dir)
+	if _err != nil {
+		return nil, _err
+	}
+
 	return NewConfig(_properties), nil
 } // gitconfig()
+
+// exists returns true if any of the configuration files of the given scope,
+// as given by Files, exist.
+func exists(scope Scope, path string) (bool, error) {
+	_files, _err := Files(scope, path)
+	if _err != nil {
+		return false, _err
+	}
+	for _, _file := range _files {
+		if _, _err := os.Stat(_file); _err == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+} // exists()
+
+// records parses the NUL-delimited output of "git config --list -z
+// --show-origin" into the list of configuration properties of the given
+// scope. Each property is described by two records, each terminated by a
+// NUL byte: the origin of the property, and the property itself. Within
+// the property record, the name is separated from its value by the first
+// "\n", and a record with no "\n" is a property with a name only. Values
+// are returned byte-exact, including any embedded newlines and surrounding
+// whitespace. Relative file origins are resolved against dir. If the
+// output is malformed, records returns InvalidOutputError.
+func records(output []byte, scope Scope, dir string) ([]Property, error) {
+	// remove the terminator of the final record, if present
+	output = bytes.TrimSuffix(output, []byte{0})
+	if len(output) == 0 {
+		return []Property{}, nil
+	}
+
+	_records := bytes.Split(output, []byte{0})
+	if len(_records)%2 != 0 {
+		return nil, InvalidOutputError
+	}
+	_properties := make([]Property, 0, len(_records)/2)
+	for _i := 0; _i < len(_records); _i += 2 {
+		// extract the file from the origin
+		//		- other origins, such as the command line, have no file
+		_file := ""
+		_origin := string(_records[_i])
+		if strings.HasPrefix(_origin, "file:") {
+			_file = strings.TrimPrefix(_origin, "file:")
+			if !filepath.IsAbs(_file) {
+				_abs, _err := filepath.Abs(filepath.Join(dir, _file))
+				if _err != nil {
+					return nil, _err
+				}
+				_file = _abs
+			}
+		}
+
+		// split the record into the name and the value
+		_parts := bytes.SplitN(_records[_i+1], []byte{'\n'}, 2)
+		if len(_parts[0]) == 0 {
+			return nil, InvalidOutputError
+		}
+		_name := string(_parts[0])
+		_value := ""
+		if len(_parts) == 2 {
+			_value = string(_parts[1])
+		}
+		_property := newProperty(
+			_name, _value, len(_parts) == 1, scope, _file, 0,
+		)
+		_properties = append(_properties, _property)
+	}
+
+	return _properties, nil
+} // records()
@@ -1,6 +1,9 @@
 package gitconfig_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/denormal/go-gitconfig"
@@ -61,3 +64,107 @@ func TestNewSystemConfig(t *testing.T) {
 		t.Fatal("expected non-empty return from NewSystemConfig(); nil found")
 	}
 } // TestNewSystemConfig()
+
+func TestNewLocalConfigValues(t *testing.T) {
+	// do we have git installed?
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	// create a temporary repository
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+
+	_, _err = gittools.RunInPath(_dir, "init", "-q")
+	if _err != nil {
+		t.Fatalf("unable to create repository: %s", _err.Error())
+	}
+
+	// set values that cannot be represented by line-based output
+	_values := map[string]string{
+		"test.multiline":  "line one\nline two\n",
+		"test.whitespace": "  padded\t",
+		"test.equals":     "a=b",
+		"test.empty":      "",
+	}
+	for _name, _value := range _values {
+		_, _err = gittools.RunInPath(_dir, "config", "--local", _name, _value)
+		if _err != nil {
+			t.Fatalf("unable to set %q: %s", _name, _err.Error())
+		}
+	}
+
+	// add a property without a value
+	_file, _err := os.OpenFile(
+		filepath.Join(_dir, ".git", "config"), os.O_APPEND|os.O_WRONLY, 0644,
+	)
+	if _err == nil {
+		_, _err = _file.WriteString("[test]\n\tflag\n")
+		_file.Close()
+	}
+	if _err != nil {
+		t.Fatalf("unable to add property without value: %s", _err.Error())
+	}
+
+	// ensure the values are returned byte-exact
+	_config, _err := gitconfig.NewLocalConfig(_dir)
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewLocalConfig(): %s",
+			_err.Error(),
+		)
+	}
+	for _name, _value := range _values {
+		_property := _config.Get(_name)
+		if _property == nil {
+			t.Fatalf("%q: unexpected nil property; expected %q", _name, _value)
+		} else if _property.String() != _value {
+			t.Fatalf(
+				"%q: unexpected value; expected %q, got %q",
+				_name, _value, _property.String(),
+			)
+		}
+	}
+
+	// ensure properties without a value are distinguished from empty values
+	if _config.Get("test.flag").HasValue() {
+		t.Fatal("unexpected value for test.flag; expected none")
+	} else if !_config.Get("test.empty").HasValue() {
+		t.Fatal("unexpected missing value for test.empty; expected empty value")
+	}
+
+	// ensure the origin of the properties is recorded
+	//		- git reports the file, but not the line
+	_expected := filepath.Join(_dir, ".git", "config")
+	if _real, _err := filepath.EvalSymlinks(_expected); _err == nil {
+		_expected = _real
+	}
+	for _, _property := range _config.All() {
+		_file := _property.File()
+		if _real, _err := filepath.EvalSymlinks(_file); _err == nil {
+			_file = _real
+		}
+		if _property.Scope() != gitconfig.LocalScope {
+			t.Fatalf(
+				"%q: unexpected scope; expected %s, got %s",
+				_property.Name(), gitconfig.LocalScope, _property.Scope(),
+			)
+		} else if _file != _expected {
+			t.Fatalf(
+				"%q: unexpected file; expected %q, got %q",
+				_property.Name(), _expected, _property.File(),
+			)
+		}
+	}
+
+	// ensure no spurious properties have been introduced
+	for _, _property := range _config.Find("line*") {
+		t.Fatalf("unexpected property %q", _property.Name())
+	}
+} // TestNewLocalConfigValues()
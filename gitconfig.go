@@ -7,8 +7,8 @@ import (
 	"github.com/denormal/go-gittools"
 )
 
-// GitConfig is the interface to git configuration, encompassing local, global
-// and system configuration for a git working copy.
+// GitConfig is the interface to git configuration, encompassing command,
+// worktree, local, global and system configuration for a git working copy.
 type GitConfig interface {
 	Config
 
@@ -25,22 +25,46 @@ type GitConfig interface {
 	// copy, Local() returns nil.
 	Local() Config
 
+	// Worktree returns the worktree git configuration for the git working
+	// copy. If the path used to initialise this GitConfig is not part of a
+	// working copy, or the repository does not enable the
+	// "extensions.worktreeConfig" extension, Worktree() returns nil.
+	Worktree() Config
+
+	// Command returns the command git configuration, given by the environment
+	// and by overrides supplied when this GitConfig was created.
+	Command() Config
+
 	// System returns the system git configuration.
 	System() Config
 
 	// Global returns the global git configuration for the current user.
 	Global() Config
+
+	// Remotes returns the remotes defined by this configuration, given by
+	// the "remote.<name>" subsections, in the order they are first defined.
+	Remotes() []Remote
+
+	// Remote returns the remote with the given name. If the remote is not
+	// defined by this configuration, Remote returns nil.
+	Remote(name string) Remote
+
+	// Branch returns the local branch with the given name, such as "main",
+	// from which its upstream and push destinations may be resolved.
+	Branch(name string) Branch
 }
 
 // gc is the implementation of the GitConfig interface
 type gc struct {
 	Config
 
-	path   string
-	root   string
-	local  Config
-	system Config
-	global Config
+	path     string
+	root     string
+	local    Config
+	worktree Config
+	command  Config
+	system   Config
+	global   Config
 }
 
 // New returns a GitConfig instance representing the git working copy in
@@ -61,9 +85,22 @@ func New() (GitConfig, error) {
 //
 // If path is "", the current working directory of the process will be used.
 func NewWithPath(path string) (GitConfig, error) {
+	return NewWithOverrides(path)
+} // NewWithPath()
+
+// NewWithOverrides returns a GitConfig instance representing the git working
+// copy path, as for NewWithPath, with the command configuration including
+// the given overrides. Each override takes the form of a "git -c" argument:
+// "name=value", or "name" for a property with no value. Overrides take
+// precedence over all other configuration, including command configuration
+// given by the environment.
+//
+// If path is "", the current working directory of the process will be used.
+func NewWithOverrides(path string, overrides ...string) (GitConfig, error) {
 	var (
-		_local Config
-		_err   error
+		_local    Config
+		_worktree Config
+		_err      error
 	)
 
 	// have we been given a path?
@@ -80,9 +117,9 @@ func NewWithPath(path string) (GitConfig, error) {
 	}
 
 	// are we in a git repository?
-	_working, _err := gittools.WorkingCopy(path)
+	_working, _err := root(path)
 	if _err != nil {
-		if _err != gittools.MissingWorkingCopyError {
+		if _err != MissingWorkingCopyError {
 			return nil, _err
 		}
 	}
@@ -93,29 +130,58 @@ func NewWithPath(path string) (GitConfig, error) {
 		if _err != nil {
 			return nil, _err
 		}
+
+		// load the worktree configuration if it's enabled
+		_enabled := _local.Get("extensions.worktreeconfig")
+		if _enabled != nil {
+			if _bool, _err := _enabled.Bool(); _err == nil && _bool {
+				_worktree, _err = worktree(path)
+				if _err != nil {
+					return nil, _err
+				}
+			}
+		}
 	}
-	_system, _err := NewSystemConfig()
+
+	// load the system and global configuration
+	//		- conditional includes are evaluated against path, rather than
+	//		  the current working directory
+	_system, _err := gitconfig(path, SystemScope)
 	if _err != nil {
 		return nil, _err
 	}
-	_global, _err := NewGlobalConfig()
+	_global, _err := gitconfig(path, GlobalScope)
+	if _err != nil {
+		return nil, _err
+	}
+	_command, _err := NewCommandConfig(overrides...)
 	if _err != nil {
 		return nil, _err
 	}
 
 	// generate the combined properties
-	//		- the properties are prioritised such that local properties
-	//		  override global properties, that override system properties
+	//		- the properties are prioritised such that command properties
+	//		  override worktree properties, that override local properties,
+	//		  that override global properties, that override system
+	//		  properties
 	_all := []Property{}
-	_all = append(_all, _system.All()...)
-	_all = append(_all, _global.All()...)
+	//		- all values are retained for multi-valued properties
+	_all = append(_all, _system.FindAll("*")...)
+	_all = append(_all, _global.FindAll("*")...)
 	if _local != nil {
-		_all = append(_all, _local.All()...)
+		_all = append(_all, _local.FindAll("*")...)
+	}
+	if _worktree != nil {
+		_all = append(_all, _worktree.FindAll("*")...)
 	}
+	_all = append(_all, _command.FindAll("*")...)
 	_config := NewConfig(_all)
 
-	return &gc{_config, path, _working, _local, _system, _global}, nil
-} // New()
+	return &gc{
+		_config, path, _working,
+		_local, _worktree, _command, _system, _global,
+	}, nil
+} // NewWithOverrides()
 
 // Path returns the absolute path used to initialise this GitConfig.
 func (g gc) Path() string { return g.path }
@@ -129,11 +195,78 @@ func (g gc) Root() string { return g.root }
 // If this GitConfig does not represent a working copy, Local will return nil.
 func (g gc) Local() Config { return g.local }
 
+// Worktree returns the worktree git configuration for the git working copy.
+// If this GitConfig does not represent a working copy, or the repository does
+// not enable the "extensions.worktreeConfig" extension, Worktree will return
+// nil.
+func (g gc) Worktree() Config { return g.worktree }
+
+// Command returns the command git configuration, given by the environment
+// and by overrides supplied when this GitConfig was created.
+func (g gc) Command() Config { return g.command }
+
 // System returns the system git configuration.
 func (g gc) System() Config { return g.system }
 
 // Global returns the global git configuration for the current user.
 func (g gc) Global() Config { return g.global }
 
+// Remotes returns the remotes defined by this configuration, in the order
+// they are first defined.
+func (g gc) Remotes() []Remote { return remotes(g.Config) }
+
+// Remote returns the remote with the given name, or nil if the remote is not
+// defined by this configuration.
+func (g gc) Remote(name string) Remote {
+	for _, _name := range g.Subsections("remote") {
+		if _name == name {
+			return newRemote(g.Config, name)
+		}
+	}
+
+	return nil
+} // Remote()
+
+// Branch returns the local branch with the given name.
+func (g gc) Branch(name string) Branch { return newBranch(g.Config, name) }
+
+//
+// private functions
+//
+
+// root returns the root directory of the git working copy containing path,
+// or MissingWorkingCopyError if path is not within a working copy. If git is
+// not installed, the working copy is located natively.
+func root(path string) (string, error) {
+	if gittools.HasGit() {
+		return gittools.WorkingCopy(path)
+	}
+
+	_repository, _err := discover(path)
+	if _err != nil {
+		return "", _err
+	}
+
+	return _repository.root, nil
+} // root()
+
+// worktree returns the worktree configuration for the working tree
+// represented by path, for repositories that enable the
+// "extensions.worktreeConfig" extension. If the working tree has no
+// "config.worktree" file, worktree returns an empty configuration.
+func worktree(path string) (Config, error) {
+	_repository, _err := newRepository(path)
+	if _err != nil {
+		return nil, _err
+	} else if _repository != nil {
+		_file := filepath.Join(_repository.gitdir, "config.worktree")
+		if _, _err := os.Stat(_file); os.IsNotExist(_err) {
+			return NewConfig(nil), nil
+		}
+	}
+
+	return NewWorktreeConfig(path)
+} // worktree()
+
 // ensure gc implemented GitConfig
 var _ GitConfig = &gc{}
@@ -3,6 +3,7 @@ package gitconfig_test
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"testing"
@@ -564,6 +565,170 @@ func TestGitConfigString(t *testing.T) {
 	}
 } // TestGitConfigString()
 
+func TestGitConfigWorktree(t *testing.T) {
+	// skip this test if git is not installed
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+
+	// create a repository with a linked worktree
+	_main := filepath.Join(_dir, "main")
+	_linked := filepath.Join(_dir, "linked")
+	for _, _args := range [][]string{
+		{"init", "-q", _main},
+		{"-C", _main, "-c", "user.name=Test", "-c", "user.email=test@example.com",
+			"commit", "-q", "--allow-empty", "-m", "initial"},
+		{"-C", _main, "worktree", "add", "-q", _linked},
+		{"-C", _main, "config", "--local", "test.value", "local"},
+	} {
+		if _, _err := gittools.RunInPath(_dir, _args...); _err != nil {
+			t.Fatalf("unable to run git %v: %s", _args, _err.Error())
+		}
+	}
+
+	// without the worktree extension, there is no worktree configuration
+	_config, _err := gitconfig.NewWithPath(_linked)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath: %s", _err.Error())
+	} else if _config.Worktree() != nil {
+		t.Fatal("unexpected worktree configuration; expected nil")
+	}
+
+	// enable the extension, and set worktree-specific values
+	for _, _args := range [][]string{
+		{"-C", _main, "config", "--local", "extensions.worktreeConfig", "true"},
+		{"-C", _linked, "config", "--worktree", "test.value", "linked"},
+	} {
+		if _, _err := gittools.RunInPath(_dir, _args...); _err != nil {
+			t.Fatalf("unable to run git %v: %s", _args, _err.Error())
+		}
+	}
+
+	// ensure the worktree configuration overrides the local configuration
+	for _path, _expected := range map[string]string{
+		_main:   "local",
+		_linked: "linked",
+	} {
+		_config, _err := gitconfig.NewWithPath(_path)
+		if _err != nil {
+			t.Fatalf(
+				"%q: unexpected error from NewWithPath: %s",
+				_path, _err.Error(),
+			)
+		} else if _config.Worktree() == nil {
+			t.Fatalf("%q: unexpected nil worktree configuration", _path)
+		}
+
+		_value := _config.Get("test.value")
+		if _value == nil {
+			t.Fatalf("%q: unexpected nil test.value", _path)
+		} else if _value.String() != _expected {
+			t.Fatalf(
+				"%q: unexpected test.value; expected %q, got %q",
+				_path, _expected, _value.String(),
+			)
+		}
+		_local := _config.Local().Get("test.value")
+		if _local == nil || _local.String() != "local" {
+			t.Fatalf("%q: unexpected local test.value", _path)
+		}
+	}
+
+	// ensure the worktree property origin is recorded
+	_config, _ = gitconfig.NewWithPath(_linked)
+	_value := _config.Worktree().Get("test.value")
+	if _value.Scope() != gitconfig.WorktreeScope {
+		t.Fatalf(
+			"unexpected scope; expected %s, got %s",
+			gitconfig.WorktreeScope, _value.Scope(),
+		)
+	} else if filepath.Base(_value.File()) != "config.worktree" {
+		t.Fatalf("unexpected file; got %q", _value.File())
+	}
+} // TestGitConfigWorktree()
+
+func TestGitConfigConditionalInclude(t *testing.T) {
+	_dir := repository(t, "[core]\n\tbare = false\n")
+	defer os.RemoveAll(_dir)
+
+	// create a global configuration with a conditional include for the
+	// repository
+	_global := filepath.Join(_dir, "global")
+	_include := filepath.Join(_dir, "include")
+	write(t, _include, "[test]\n\tincluded = yes\n")
+	write(t, _global,
+		"[includeIf \"gitdir:"+_dir+"/\"]\n\tpath = "+_include+"\n",
+	)
+
+	// restore the environment once we're done
+	_original, _ok := os.LookupEnv("GIT_CONFIG_GLOBAL")
+	defer func() {
+		if _ok {
+			os.Setenv("GIT_CONFIG_GLOBAL", _original)
+		} else {
+			os.Unsetenv("GIT_CONFIG_GLOBAL")
+		}
+	}()
+	os.Setenv("GIT_CONFIG_GLOBAL", _global)
+
+	// ensure the include is evaluated against the repository, rather than
+	// the current working directory
+	_config, _err := gitconfig.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("%q: unexpected error from NewWithPath: %s", _dir, _err)
+	}
+	for _name, _config := range map[string]gitconfig.Config{
+		"config": _config,
+		"global": _config.Global(),
+	} {
+		_included := _config.Get("test.included")
+		if _included == nil {
+			t.Errorf("%s: unexpected nil test.included", _name)
+		} else if _included.String() != "yes" {
+			t.Errorf(
+				"%s: unexpected test.included; expected %q, got %q",
+				_name, "yes", _included.String(),
+			)
+		}
+	}
+} // TestGitConfigConditionalInclude()
+
+func TestGitConfigMissingFiles(t *testing.T) {
+	_dir := repository(t, "[core]\n\tbare = false\n")
+	defer os.RemoveAll(_dir)
+
+	// name system and global configuration files that do not exist
+	defer unset(_DISCOVERY...)()
+	os.Setenv("GIT_CONFIG_SYSTEM", filepath.Join(_dir, "system"))
+	os.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(_dir, "global"))
+
+	// ensure missing files are treated as empty configuration
+	_config, _err := gitconfig.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("%q: unexpected error from NewWithPath: %s", _dir, _err)
+	}
+	for _name, _config := range map[string]gitconfig.Config{
+		"system": _config.System(),
+		"global": _config.Global(),
+	} {
+		if _all := _config.All(); len(_all) != 0 {
+			t.Errorf("%s: unexpected properties %v", _name, _all)
+		}
+	}
+	if _bare := _config.Get("core.bare"); _bare == nil {
+		t.Error("unexpected nil core.bare")
+	}
+} // TestGitConfigMissingFiles()
+
 //
 // helper methods
 //
@@ -0,0 +1,264 @@
+package gitconfig
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	IncludeCycleError   = errors.New("configuration include cycle")
+	IncludeDepthError   = errors.New("exceeded maximum include depth")
+	InvalidIncludeError = errors.New("invalid configuration include")
+)
+
+// _MAX_INCLUDE_DEPTH is the maximum depth of nested includes, as for git
+const _MAX_INCLUDE_DEPTH = 10
+
+// loader loads configuration files natively, resolving the include.path and
+// includeIf.<condition>.path directives as described in
+// https://git-scm.com/docs/git-config#_includes.
+type loader struct {
+	scope      Scope       // the scope of the configuration files
+	repository *repository // the repository for gitdir: and onbranch:
+	files      []string    // all configuration files, for hasconfig:
+	urls       []string    // remote URLs, for hasconfig:
+	resolved   bool        // true if urls have been determined
+	hasconfig  bool        // false if hasconfig: conditions are disabled
+	stack      []string    // the files currently being loaded
+}
+
+// newLoader returns a loader for the configuration files of the given scope,
+// where r is the repository used to evaluate conditional includes, and may
+// be nil.
+func newLoader(scope Scope, r *repository, files ...string) *loader {
+	return &loader{scope: scope, repository: r, files: files, hasconfig: true}
+} // newLoader()
+
+// load returns the properties defined by the configuration file at path,
+// including the properties of all files it includes.
+func (l *loader) load(path string) ([]Property, error) {
+	_data, _err := ioutil.ReadFile(path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return l.parse(path, _data)
+} // load()
+
+// parse returns the properties defined by the configuration data read from
+// the file path, including the properties of all files it includes.
+// Included properties immediately follow the directive that includes them.
+func (l *loader) parse(path string, data []byte) ([]Property, error) {
+	// ensure we are not including a file we are already loading
+	_file := path
+	if path != "" {
+		_abs, _err := filepath.Abs(path)
+		if _err != nil {
+			return nil, _err
+		}
+		_file = _abs
+		if _real, _err := filepath.EvalSymlinks(_abs); _err == nil {
+			_file = _real
+		}
+		for _, _loading := range l.stack {
+			if _loading == _file {
+				return nil, IncludeCycleError
+			}
+		}
+	}
+	if len(l.stack) > _MAX_INCLUDE_DEPTH {
+		return nil, IncludeDepthError
+	}
+	l.stack = append(l.stack, _file)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	_properties := make([]Property, 0)
+	_err := newParser(path, data).parse(func(e *element) error {
+		if e.token != _ENTRY {
+			return nil
+		}
+		_property := newProperty(
+			e.name(), e.value, e.novalue, l.scope, path, e.line,
+		)
+		_properties = append(_properties, _property)
+
+		// is this an include directive?
+		_include, _err := l.include(path, e)
+		if _err != nil || _include == "" {
+			return _err
+		}
+		_included, _err := l.load(_include)
+		if _err != nil {
+			// missing include files are ignored
+			if os.IsNotExist(_err) {
+				return nil
+			}
+			return _err
+		}
+		_properties = append(_properties, _included...)
+
+		return nil
+	})
+	if _err != nil {
+		return nil, _err
+	}
+
+	return _properties, nil
+} // parse()
+
+// include returns the path of the file to include for the entry e of the
+// configuration file path, or the empty string if e is not an include
+// directive, or its condition is not satisfied.
+func (l *loader) include(path string, e *element) (string, error) {
+	_unconditional := e.section == "include" && e.subsection == ""
+	_conditional := e.section == "includeif" && e.subsection != ""
+	if e.variable != "path" || !(_unconditional || _conditional) {
+		return "", nil
+	} else if _conditional {
+		_ok, _err := l.condition(path, e.subsection)
+		if _err != nil || !_ok {
+			return "", _err
+		}
+	}
+
+	// the include must have a value
+	if e.novalue || e.value == "" {
+		return "", InvalidIncludeError
+	}
+
+	// includes are relative to the including file
+	_include, _err := expand(e.value)
+	if _err != nil {
+		return "", _err
+	} else if !filepath.IsAbs(_include) {
+		if path == "" {
+			return "", InvalidIncludeError
+		}
+		_include = filepath.Join(filepath.Dir(path), _include)
+	}
+
+	return _include, nil
+} // include()
+
+// condition returns true if the includeIf condition is satisfied for the
+// configuration file path. Unknown conditions are never satisfied.
+func (l *loader) condition(path, condition string) (bool, error) {
+	_parts := strings.SplitN(condition, ":", 2)
+	if len(_parts) != 2 {
+		return false, nil
+	}
+
+	_pattern := _parts[1]
+	switch _parts[0] {
+	case "gitdir":
+		return l.gitdir(path, _pattern, false)
+	case "gitdir/i":
+		return l.gitdir(path, _pattern, true)
+	case "onbranch":
+		if l.repository == nil {
+			return false, nil
+		}
+		_branch := l.repository.head()
+		if _branch == "" {
+			return false, nil
+		} else if strings.HasSuffix(_pattern, "/") {
+			_pattern += "**"
+		}
+		return wildmatch(_pattern, _branch, false), nil
+	case "hasconfig":
+		const _REMOTE = "remote.*.url:"
+		if !strings.HasPrefix(_pattern, _REMOTE) || !l.hasconfig {
+			return false, nil
+		}
+		_urls, _err := l.remotes()
+		if _err != nil {
+			return false, _err
+		}
+		_pattern = strings.TrimPrefix(_pattern, _REMOTE)
+		for _, _url := range _urls {
+			if wildmatch(_pattern, _url, false) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+} // condition()
+
+// gitdir returns true if the git directory of the repository matches the
+// gitdir: condition pattern of the configuration file path.
+func (l *loader) gitdir(path, pattern string, fold bool) (bool, error) {
+	if l.repository == nil {
+		return false, nil
+	}
+
+	// prepare the pattern
+	//		- "./" is relative to the including file
+	//		- patterns that are not absolute may match at any depth
+	//		- a trailing "/" matches everything within the directory
+	_pattern, _err := expand(pattern)
+	if _err != nil {
+		return false, _err
+	}
+	if strings.HasPrefix(_pattern, "./") {
+		if path == "" {
+			return false, InvalidIncludeError
+		}
+		_pattern = filepath.Dir(path) + _pattern[1:]
+	} else if !filepath.IsAbs(_pattern) {
+		_pattern = "**/" + _pattern
+	}
+	if strings.HasSuffix(_pattern, "/") {
+		_pattern += "**"
+	}
+
+	// match against the git directory, as well as its real path
+	_gitdir := l.repository.gitdir
+	if wildmatch(_pattern, _gitdir, fold) {
+		return true, nil
+	}
+	_real, _err := filepath.EvalSymlinks(_gitdir)
+	if _err == nil && wildmatch(_pattern, _real, fold) {
+		return true, nil
+	}
+
+	return false, nil
+} // gitdir()
+
+// remotes returns the list of remote URLs defined by the configuration
+// files of the loader, used to evaluate hasconfig: conditions.
+func (l *loader) remotes() ([]string, error) {
+	if l.resolved {
+		return l.urls, nil
+	}
+
+	// load the configuration files, ignoring hasconfig: conditions
+	_loader := newLoader(l.scope, l.repository, l.files...)
+	_loader.hasconfig = false
+	_urls := make([]string, 0)
+	for _, _file := range l.files {
+		_properties, _err := _loader.load(_file)
+		if _err != nil {
+			if os.IsNotExist(_err) {
+				continue
+			}
+			return nil, _err
+		}
+
+		for _, _property := range _properties {
+			_section, _subsection, _variable, _err := split(_property.Name())
+			if _err == nil &&
+				_section == "remote" && _subsection != "" &&
+				_variable == "url" {
+				_urls = append(_urls, _property.String())
+			}
+		}
+	}
+	l.urls = _urls
+	l.resolved = true
+
+	return l.urls, nil
+} // remotes()
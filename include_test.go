@@ -0,0 +1,190 @@
+package gitconfig_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+func TestIncludes(t *testing.T) {
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+	_dir, _ = filepath.EvalSymlinks(_dir)
+
+	// create a repository with a checked-out branch
+	_root := filepath.Join(_dir, "work", "project")
+	_gitdir := filepath.Join(_root, ".git")
+	write(t, filepath.Join(_gitdir, "HEAD"), "ref: refs/heads/feature/x\n")
+
+	// override the home directory
+	_home := os.Getenv("HOME")
+	defer os.Setenv("HOME", _home)
+	os.Setenv("HOME", _dir)
+
+	write(t, filepath.Join(_dir, "config"), ""+
+		"[user]\n"+
+		"\tname = Default\n"+
+		"[include]\n"+
+		"\tpath = included/user\n"+
+		"\tpath = missing\n"+
+		"[includeIf \"gitdir:~/work/\"]\n"+
+		"\tpath = ~/work.config\n"+
+		"[includeIf \"gitdir:other/\"]\n"+
+		"\tpath = never.config\n"+
+		"[includeIf \"gitdir/i:PROJECT/.GIT\"]\n"+
+		"\tpath = project.config\n"+
+		"[includeIf \"gitdir:./work/project/.git\"]\n"+
+		"\tpath = relative.config\n"+
+		"[includeIf \"onbranch:feature/\"]\n"+
+		"\tpath = branch.config\n"+
+		"[includeIf \"onbranch:main\"]\n"+
+		"\tpath = never.config\n"+
+		"[includeIf \"hasconfig:remote.*.url:https://example.com/**\"]\n"+
+		"\tpath = remote.config\n"+
+		"[includeIf \"unknown:condition\"]\n"+
+		"\tpath = never.config\n"+
+		"[remote \"origin\"]\n"+
+		"\turl = https://example.com/x.git\n",
+	)
+	write(t, filepath.Join(_dir, "included", "user"), ""+
+		"[user]\n"+
+		"\tname = Included\n"+
+		"[include]\n"+
+		"\tpath = nested\n",
+	)
+	write(t, filepath.Join(_dir, "included", "nested"), "[test]\n\tnested\n")
+	write(t, filepath.Join(_dir, "work.config"), "[test]\n\twork\n")
+	write(t, filepath.Join(_dir, "project.config"), "[test]\n\tproject\n")
+	write(t, filepath.Join(_dir, "relative.config"), "[test]\n\trelative\n")
+	write(t, filepath.Join(_dir, "branch.config"), "[test]\n\tbranch\n")
+	write(t, filepath.Join(_dir, "remote.config"), "[test]\n\tremote\n")
+	write(t, filepath.Join(_dir, "never.config"), "[test]\n\tnever\n")
+
+	// ensure the includes are resolved
+	_path := filepath.Join(_dir, "config")
+	_config, _err := gitconfig.NewConfigFromFileWithIncludes(_path, _root)
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewConfigFromFileWithIncludes(): %s",
+			_err.Error(),
+		)
+	}
+	_name := _config.Get("user.name")
+	if _name == nil || _name.String() != "Included" {
+		t.Fatalf("unexpected user.name; expected %q, got %v", "Included", _name)
+	}
+	if _name.File() != filepath.Join(_dir, "included", "user") {
+		t.Fatalf(
+			"unexpected user.name file; expected %q, got %q",
+			filepath.Join(_dir, "included", "user"), _name.File(),
+		)
+	} else if _name.Line() != 2 {
+		t.Fatalf("unexpected user.name line; expected 2, got %d", _name.Line())
+	}
+	_expected := []string{
+		"test.nested", "test.work", "test.project", "test.relative",
+		"test.branch", "test.remote",
+	}
+	_all := _config.FindAll("test.*")
+	if len(_all) != len(_expected) {
+		t.Fatalf(
+			"unexpected included properties; expected %v, got %s",
+			_expected, _config.String(),
+		)
+	}
+	for _i, _property := range _all {
+		if _property.Name() != _expected[_i] {
+			t.Fatalf(
+				"unexpected included property; expected %q, got %q",
+				_expected[_i], _property.Name(),
+			)
+		}
+	}
+
+	// without a repository, only unconditional and hasconfig: includes
+	// are resolved
+	_config, _err = gitconfig.NewConfigFromFileWithIncludes(_path, "")
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewConfigFromFileWithIncludes(): %s",
+			_err.Error(),
+		)
+	}
+	_expected = []string{"test.nested", "test.remote"}
+	_all = _config.FindAll("test.*")
+	if len(_all) != len(_expected) {
+		t.Fatalf(
+			"unexpected included properties; expected %v, got %s",
+			_expected, _config.String(),
+		)
+	}
+} // TestIncludes()
+
+func TestIncludeErrors(t *testing.T) {
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+
+	// create an include cycle
+	write(t, filepath.Join(_dir, "a"), "[include]\n\tpath = b\n")
+	write(t, filepath.Join(_dir, "b"), "[include]\n\tpath = a\n")
+
+	// create a chain of includes that is too deep
+	for _i := 0; _i <= 11; _i++ {
+		write(
+			t,
+			filepath.Join(_dir, fmt.Sprintf("depth%d", _i)),
+			fmt.Sprintf("[include]\n\tpath = depth%d\n", _i+1),
+		)
+	}
+
+	// create an include without a value
+	write(t, filepath.Join(_dir, "invalid"), "[include]\n\tpath\n")
+
+	for _file, _expected := range map[string]error{
+		"a":       gitconfig.IncludeCycleError,
+		"depth1":  nil,
+		"depth0":  gitconfig.IncludeDepthError,
+		"invalid": gitconfig.InvalidIncludeError,
+	} {
+		_path := filepath.Join(_dir, _file)
+		_, _err := gitconfig.NewConfigFromFileWithIncludes(_path, "")
+		if _err != _expected {
+			t.Fatalf(
+				"%s: unexpected error; expected %v, got %v",
+				_file, _expected, _err,
+			)
+		}
+	}
+} // TestIncludeErrors()
+
+//
+// helper functions
+//
+
+// write writes content to the file at path, creating any missing parent
+// directories.
+func write(t *testing.T, path, content string) {
+	_err := os.MkdirAll(filepath.Dir(path), 0755)
+	if _err == nil {
+		_err = ioutil.WriteFile(path, []byte(content), 0644)
+	}
+	if _err != nil {
+		t.Fatalf("unable to write %q: %s", path, _err.Error())
+	}
+} // write()
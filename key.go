@@ -0,0 +1,128 @@
+package gitconfig
+
+import (
+	"strings"
+)
+
+// Key represents the name of a configuration property, composed of section,
+// optional subsection and variable names. For example, the property
+// "url.https://example.com/.insteadOf" has the section name "url", the
+// subsection name "https://example.com/" and the variable name "insteadof".
+type Key interface {
+	// Section returns the lower-cased section name of the key.
+	Section() string
+
+	// Subsection returns the subsection name of the key, or the empty string
+	// if the key has no subsection. Subsection names are case-sensitive, and
+	// may contain dots.
+	Subsection() string
+
+	// Variable returns the lower-cased variable name of the key.
+	Variable() string
+
+	// String returns the canonical property name of the key.
+	String() string
+}
+
+// key is the implementation of the Key interface.
+type key struct {
+	section    string
+	subsection string
+	variable   string
+}
+
+// NewKey returns the Key instance for the given section, subsection and
+// variable names. The section and variable names are lower-cased, as they
+// are by git. If subsection is "", the key has no subsection. If the names
+// are not valid, NewKey returns InvalidKeyError.
+func NewKey(section, subsection, variable string) (Key, error) {
+	if !valid(section, true) || !valid(variable, false) {
+		return nil, InvalidKeyError
+	} else if strings.ContainsAny(subsection, "\n\x00") {
+		return nil, InvalidKeyError
+	}
+
+	return &key{
+		section:    strings.ToLower(section),
+		subsection: subsection,
+		variable:   strings.ToLower(variable),
+	}, nil
+} // NewKey()
+
+// ParseKey returns the Key instance for the given property name. The section
+// name is taken up to the first dot of name, and the variable name from the
+// last dot, with everything in between forming the subsection name. If name
+// is not a valid property name, ParseKey returns InvalidKeyError.
+func ParseKey(name string) (Key, error) {
+	_section, _subsection, _variable, _err := split(name)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return NewKey(_section, _subsection, _variable)
+} // ParseKey()
+
+// Section returns the lower-cased section name of the key.
+func (k key) Section() string { return k.section }
+
+// Subsection returns the subsection name of the key, or the empty string
+// if the key has no subsection.
+func (k key) Subsection() string { return k.subsection }
+
+// Variable returns the lower-cased variable name of the key.
+func (k key) Variable() string { return k.variable }
+
+// String returns the canonical property name of the key.
+func (k key) String() string {
+	if k.section == "" {
+		return k.variable
+	} else if k.subsection == "" {
+		return k.section + "." + k.variable
+	}
+
+	return k.section + "." + k.subsection + "." + k.variable
+} // String()
+
+// ensure key conforms to the Key interface
+var _ Key = &key{}
+
+//
+// private functions
+//
+
+// newKey returns the Key instance for the given property name without
+// validation, splitting the name at its first and last dots. If name has no
+// dots, it is treated as a variable name.
+func newKey(name string) Key {
+	_section, _subsection, _variable, _ok := fields(name)
+	if !_ok {
+		return &key{variable: strings.ToLower(name)}
+	}
+
+	_key := &key{
+		section:  strings.ToLower(_section),
+		variable: strings.ToLower(_variable),
+	}
+	if _subsection != nil {
+		_key.subsection = *_subsection
+	}
+
+	return _key
+} // newKey()
+
+// fields splits the property name into its section, subsection and variable
+// names at the first and last dots of name, without validation or case
+// folding. If name has only one dot, the subsection is nil, and if name has
+// no dots, fields returns false.
+func fields(name string) (string, *string, string, bool) {
+	_first := strings.Index(name, ".")
+	_last := strings.LastIndex(name, ".")
+	if _first == -1 {
+		return "", nil, name, false
+	} else if _first == _last {
+		return name[:_first], nil, name[_last+1:], true
+	}
+
+	_subsection := name[_first+1 : _last]
+	return name[:_first], &_subsection, name[_last+1:], true
+} // fields()
@@ -0,0 +1,83 @@
+package gitconfig_test
+
+import (
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+func TestParseKey(t *testing.T) {
+	for _name, _expected := range map[string][]string{
+		"core.autocrlf":                     {"core", "", "autocrlf", "core.autocrlf"},
+		"Core.AutoCRLF":                     {"core", "", "autocrlf", "core.autocrlf"},
+		"remote.Origin.URL":                 {"remote", "Origin", "url", "remote.Origin.url"},
+		"url.https://github.com/.insteadOf": {"url", "https://github.com/", "insteadof", "url.https://github.com/.insteadof"},
+		"branch.feature/x.y.remote":         {"branch", "feature/x.y", "remote", "branch.feature/x.y.remote"},
+		"a.b c.d":                           {"a", "b c", "d", "a.b c.d"},
+		"core":                              nil,
+		"core.":                             nil,
+		".name":                             nil,
+		"core.1name":                        nil,
+		"co_re.name":                        nil,
+		"a.b\nc.d":                          nil,
+	} {
+		_key, _err := gitconfig.ParseKey(_name)
+		if _expected == nil {
+			if _err != gitconfig.InvalidKeyError {
+				t.Fatalf(
+					"%q: unexpected error; expected %v, got %v",
+					_name, gitconfig.InvalidKeyError, _err,
+				)
+			}
+			continue
+		} else if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _name, _err.Error())
+		}
+
+		_got := []string{
+			_key.Section(), _key.Subsection(), _key.Variable(), _key.String(),
+		}
+		for _i := range _got {
+			if _got[_i] != _expected[_i] {
+				t.Fatalf(
+					"%q: unexpected key; expected %q, got %q",
+					_name, _expected, _got,
+				)
+			}
+		}
+	}
+} // TestParseKey()
+
+func TestNewKey(t *testing.T) {
+	_key, _err := gitconfig.NewKey("URL", "https://Example.com/", "pushInsteadOf")
+	if _err != nil {
+		t.Fatalf("unexpected error from NewKey(): %s", _err.Error())
+	} else if _key.String() != "url.https://Example.com/.pushinsteadof" {
+		t.Fatalf("unexpected key; got %q", _key.String())
+	}
+
+	// ensure invalid names are rejected
+	for _, _names := range [][]string{
+		{"", "", "name"},
+		{"section", "", ""},
+		{"sec.tion", "", "name"},
+		{"section", "", "na.me"},
+		{"section", "sub\nsection", "name"},
+	} {
+		_, _err := gitconfig.NewKey(_names[0], _names[1], _names[2])
+		if _err != gitconfig.InvalidKeyError {
+			t.Fatalf(
+				"%q: unexpected error; expected %v, got %v",
+				_names, gitconfig.InvalidKeyError, _err,
+			)
+		}
+	}
+
+	// ensure properties expose their key
+	_property := gitconfig.NewProperty("Branch.feature/X.y.Remote", "origin")
+	_key = _property.Key()
+	if _key.Section() != "branch" || _key.Subsection() != "feature/X.y" ||
+		_key.Variable() != "remote" {
+		t.Fatalf("unexpected property key %q", _key.String())
+	}
+} // TestNewKey()
@@ -0,0 +1,281 @@
+package gitconfig
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	InvalidMarshalError  = errors.New("marshal requires a struct or non-nil struct pointer")
+	InvalidDurationError = errors.New("duration is not a whole number of seconds")
+)
+
+// Marshal returns the git configuration file content for the struct v, or
+// the struct pointed to by v, using the same struct tags as Unmarshal.
+// Fields are written using the same conversions as Unmarshal, so that the
+// returned content loads back identically. Properties are grouped into
+// sections in the order their fields are defined, and map entries are
+// written in key order. Nil pointers, slices and maps are omitted, as are
+// fields with the "omitempty" tag option holding their zero value. A
+// Property field without a value is written as a variable name alone.
+//
+// If v is not a struct, or a non-nil pointer to a struct, Marshal returns
+// InvalidMarshalError. If a field cannot be written, Marshal returns a
+// *PropertyError naming the field's property, with UnsupportedTypeError
+// for fields of unsupported types, InvalidDurationError for durations that
+// are not a whole number of seconds, and InvalidKeyError for invalid
+// property names.
+func Marshal(v interface{}) ([]byte, error) {
+	_value := reflect.ValueOf(v)
+	if _value.Kind() == reflect.Ptr && !_value.IsNil() {
+		_value = _value.Elem()
+	}
+	if _value.Kind() != reflect.Struct {
+		return nil, InvalidMarshalError
+	}
+
+	_marshaler := &marshaler{sections: make(map[section][]variable)}
+	_err := _marshaler.structure("", _value)
+	if _err != nil {
+		return nil, _err
+	}
+
+	// generate the configuration content
+	_bytes := bytes.NewBuffer(nil)
+	for _, _section := range _marshaler.order {
+		_bytes.Write(header("", _section.section, _section.subsection))
+		_bytes.WriteString("\n")
+		for _, _variable := range _marshaler.sections[_section] {
+			if _variable.value == nil {
+				_bytes.WriteString("\t" + _variable.name + "\n")
+			} else {
+				_bytes.Write(
+					entry([]byte("\t"), _variable.name, *_variable.value),
+				)
+			}
+		}
+	}
+
+	return _bytes.Bytes(), nil
+} // Marshal()
+
+//
+// private types
+//
+
+// marshaler collects the properties of Go values, grouped by section.
+type marshaler struct {
+	order    []section
+	sections map[section][]variable
+}
+
+// section identifies a section of the configuration.
+type section struct {
+	section    string
+	subsection string
+}
+
+// variable is a single variable of a section, with a nil value for a
+// variable without a value.
+type variable struct {
+	name  string
+	value *string
+}
+
+//
+// private methods
+//
+
+// structure collects the properties of the fields of the struct v, whose
+// field tags are relative to prefix.
+func (m *marshaler) structure(prefix string, v reflect.Value) error {
+	_type := v.Type()
+	for _i := 0; _i < _type.NumField(); _i++ {
+		_field := _type.Field(_i)
+		_tag, _ok := parseTag(_field.Tag.Get("gitconfig"))
+		if !_ok || _field.PkgPath != "" {
+			continue
+		}
+		_name := _tag.name
+		if prefix != "" {
+			_name = prefix + "." + _name
+		}
+		_value := v.Field(_i)
+		if _tag.omitempty && isZero(_value) {
+			continue
+		}
+
+		var _err error
+		switch {
+		case _value.Kind() == reflect.Struct && !converted(_value.Type()):
+			_err = m.structure(_name, _value)
+
+		case _value.Kind() == reflect.Map:
+			_err = m.mapping(_name, _value)
+
+		case _value.Kind() == reflect.Slice:
+			for _j := 0; _j < _value.Len() && _err == nil; _j++ {
+				_err = m.add(_name, _value.Index(_j))
+			}
+
+		default:
+			_err = m.add(_name, _value)
+		}
+		if _err != nil {
+			return _err
+		}
+	}
+
+	return nil
+} // structure()
+
+// mapping collects the properties of the map v, for the section or
+// subsection name. Maps of structs have an entry for each subsection of the
+// section name, while other maps have an entry for each variable.
+func (m *marshaler) mapping(name string, v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return &PropertyError{Name: name, Err: UnsupportedTypeError}
+	}
+
+	// sort the map keys
+	_keys := make([]string, 0, v.Len())
+	_values := make(map[string]reflect.Value)
+	for _, _key := range v.MapKeys() {
+		_keys = append(_keys, _key.String())
+		_values[_key.String()] = v.MapIndex(_key)
+	}
+	sort.Strings(_keys)
+
+	// is this a map of subsections?
+	_struct := v.Type().Elem()
+	if _struct.Kind() == reflect.Ptr {
+		_struct = _struct.Elem()
+	}
+	for _, _key := range _keys {
+		_value := _values[_key]
+		if _struct.Kind() == reflect.Struct && !converted(_struct) {
+			if _value.Kind() == reflect.Ptr {
+				if _value.IsNil() {
+					continue
+				}
+				_value = _value.Elem()
+			}
+			_err := m.structure(name+"."+_key, _value)
+			if _err != nil {
+				return _err
+			}
+		} else if _err := m.add(name+"."+_key, _value); _err != nil {
+			return _err
+		}
+	}
+
+	return nil
+} // mapping()
+
+// add collects the named property with the value of v.
+func (m *marshaler) add(name string, v reflect.Value) error {
+	_key, _err := ParseKey(name)
+	if _err != nil {
+		return &PropertyError{Name: name, Err: _err}
+	}
+
+	// nil pointers and properties are omitted
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+	}
+
+	// convert the value
+	_value, _err := format(v)
+	if _err != nil {
+		return &PropertyError{Name: _key.String(), Err: _err}
+	}
+
+	// add the variable to its section
+	//		- the variable name is written as given
+	_section := section{_key.Section(), _key.Subsection()}
+	if _, _ok := m.sections[_section]; !_ok {
+		m.order = append(m.order, _section)
+	}
+	m.sections[_section] = append(m.sections[_section], variable{
+		name:  name[strings.LastIndex(name, ".")+1:],
+		value: _value,
+	})
+
+	return nil
+} // add()
+
+//
+// private functions
+//
+
+// format returns the configuration value of v, or nil for a value that
+// should be written without a value. If v cannot be written, format
+// returns UnsupportedTypeError.
+func format(v reflect.Value) (*string, error) {
+	var _value string
+	switch _type := v.Type(); {
+	case _type == _PROPERTY:
+		if !v.Interface().(Property).HasValue() {
+			return nil, nil
+		}
+		_value = v.Interface().(Property).String()
+	case _type == _DURATION:
+		// durations are written in whole seconds, as git has no finer
+		// units
+		_duration := v.Interface().(time.Duration)
+		if _duration%time.Second != 0 {
+			return nil, InvalidDurationError
+		}
+		_value = strconv.FormatInt(int64(_duration/time.Second), 10)
+	case _type == _COLOR:
+		_value = v.Interface().(Color).String()
+	case _type == _BOOL_OR_INT:
+		_result := v.Interface().(BoolOrInt)
+		if _result.IsBool {
+			_value = strconv.FormatBool(_result.Bool)
+		} else {
+			_value = strconv.Itoa(_result.Int)
+		}
+	case _type == _BOOL_OR_STRING:
+		_result := v.Interface().(BoolOrString)
+		if _result.IsBool {
+			_value = strconv.FormatBool(_result.Bool)
+		} else {
+			_value = _result.String
+		}
+
+	default:
+		switch v.Kind() {
+		case reflect.String:
+			_value = v.String()
+		case reflect.Bool:
+			_value = strconv.FormatBool(v.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Int64:
+			_value = strconv.FormatInt(v.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64:
+			_value = strconv.FormatUint(v.Uint(), 10)
+		case reflect.Ptr:
+			return format(v.Elem())
+		default:
+			return nil, UnsupportedTypeError
+		}
+	}
+
+	return &_value, nil
+} // format()
+
+// isZero returns true if v holds the zero value of its type.
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(
+		v.Interface(), reflect.Zero(v.Type()).Interface(),
+	)
+} // isZero()
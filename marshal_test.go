@@ -0,0 +1,214 @@
+package gitconfig_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+type provisioned struct {
+	Name  string `gitconfig:"user.name"`
+	Email string `gitconfig:"user.email,omitempty"`
+	Core  struct {
+		Bare      bool                   `gitconfig:"bare"`
+		AutoCRLF  gitconfig.BoolOrString `gitconfig:"autocrlf"`
+		Threshold int64                  `gitconfig:"bigFileThreshold"`
+		Editor    *string                `gitconfig:"editor"`
+	} `gitconfig:"core"`
+	Remotes map[string]*struct {
+		URL   string   `gitconfig:"url"`
+		Fetch []string `gitconfig:"fetch"`
+	} `gitconfig:"remote"`
+	Aliases map[string]string   `gitconfig:"alias"`
+	Expire  time.Duration       `gitconfig:"gc.pruneExpire"`
+	Color   gitconfig.Color     `gitconfig:"color.diff.meta"`
+	Log     gitconfig.BoolOrInt `gitconfig:"merge.log"`
+	Comment string              `gitconfig:"test.comment"`
+	Escaped string              `gitconfig:"test.escaped"`
+}
+
+func TestMarshal(t *testing.T) {
+	_color, _ := gitconfig.ParseColor("bold yellow")
+	_editor := "vim"
+	_value := provisioned{Name: "A. N. Other"}
+	_value.Core.Bare = true
+	_value.Core.AutoCRLF = gitconfig.BoolOrString{String: "input"}
+	_value.Core.Threshold = 512 << 20
+	_value.Core.Editor = &_editor
+	_value.Remotes = map[string]*struct {
+		URL   string   `gitconfig:"url"`
+		Fetch []string `gitconfig:"fetch"`
+	}{
+		"origin": {
+			URL: "https://example.com/x.git",
+			Fetch: []string{
+				"+refs/heads/*:refs/remotes/origin/*",
+				"+refs/tags/*:refs/tags/*",
+			},
+		},
+		"Quoted \"name\"": {URL: "https://example.com/y.git"},
+	}
+	_value.Aliases = map[string]string{"st": "status", "co": "checkout"}
+	_value.Expire = 14 * 24 * time.Hour
+	_value.Color = _color
+	_value.Log = gitconfig.BoolOrInt{Int: 20}
+	_value.Comment = "value ; with # comment "
+	_value.Escaped = "tab\tand\nnewline \\ \"quoted\""
+
+	_bytes, _err := gitconfig.Marshal(&_value)
+	if _err != nil {
+		t.Fatalf("unexpected error from Marshal(): %s", _err.Error())
+	}
+	_expected := "" +
+		"[user]\n" +
+		"\tname = A. N. Other\n" +
+		"[core]\n" +
+		"\tbare = true\n" +
+		"\tautocrlf = input\n" +
+		"\tbigFileThreshold = 536870912\n" +
+		"\teditor = vim\n" +
+		"[remote \"Quoted \\\"name\\\"\"]\n" +
+		"\turl = https://example.com/y.git\n" +
+		"[remote \"origin\"]\n" +
+		"\turl = https://example.com/x.git\n" +
+		"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+		"\tfetch = +refs/tags/*:refs/tags/*\n" +
+		"[alias]\n" +
+		"\tco = checkout\n" +
+		"\tst = status\n" +
+		"[gc]\n" +
+		"\tpruneExpire = 1209600\n" +
+		"[color \"diff\"]\n" +
+		"\tmeta = bold yellow\n" +
+		"[merge]\n" +
+		"\tlog = 20\n" +
+		"[test]\n" +
+		"\tcomment = \"value ; with # comment \"\n" +
+		"\tescaped = tab\\tand\\nnewline \\\\ \\\"quoted\\\"\n"
+	if string(_bytes) != _expected {
+		t.Fatalf(
+			"unexpected configuration; expected %q, got %q",
+			_expected, string(_bytes),
+		)
+	}
+
+	// ensure the configuration loads back identically
+	_config, _err := gitconfig.NewConfigFromReader(strings.NewReader(string(_bytes)))
+	if _err != nil {
+		t.Fatalf("unexpected error from NewConfigFromReader(): %s", _err.Error())
+	}
+	var _loaded provisioned
+	_err = gitconfig.Unmarshal(_config, &_loaded)
+	if _err != nil {
+		t.Fatalf("unexpected error from Unmarshal(): %s", _err.Error())
+	} else if !reflect.DeepEqual(_loaded, _value) {
+		t.Fatalf("unexpected round trip; expected %+v, got %+v", _value, _loaded)
+	}
+} // TestMarshal()
+
+func TestMarshalWhitespace(t *testing.T) {
+	type whitespace struct {
+		Values map[string]string `gitconfig:"test"`
+	}
+
+	// ensure values with whitespace survive a round trip
+	_value := whitespace{Values: map[string]string{
+		"single":   "one two",
+		"double":   "one  two",
+		"leading":  " leading",
+		"trailing": "trailing ",
+		"return":   "carriage\rreturn",
+		"vertical": "vertical\vtab",
+		"feed":     "form\ffeed",
+		"tab":      "tab\tseparated",
+		"newline":  "new\nline",
+		"mixed":    " \r\v\f\t\n ",
+	}}
+	_bytes, _err := gitconfig.Marshal(_value)
+	if _err != nil {
+		t.Fatalf("unexpected error from Marshal(): %s", _err.Error())
+	}
+	_config, _err := gitconfig.NewConfigFromReader(
+		strings.NewReader(string(_bytes)),
+	)
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewConfigFromReader(): %s",
+			_err.Error(),
+		)
+	}
+
+	var _loaded whitespace
+	_err = gitconfig.Unmarshal(_config, &_loaded)
+	if _err != nil {
+		t.Fatalf("unexpected error from Unmarshal(): %s", _err.Error())
+	}
+	for _name, _expected := range _value.Values {
+		if _got := _loaded.Values[_name]; _got != _expected {
+			t.Errorf(
+				"%s: unexpected round trip; expected %q, got %q",
+				_name, _expected, _got,
+			)
+		}
+	}
+} // TestMarshalWhitespace()
+
+func TestMarshalErrors(t *testing.T) {
+	for _i, _test := range []struct {
+		v   interface{}
+		err error
+	}{
+		{nil, gitconfig.InvalidMarshalError},
+		{"string", gitconfig.InvalidMarshalError},
+		{(*struct{})(nil), gitconfig.InvalidMarshalError},
+		{
+			struct {
+				F float64 `gitconfig:"test.float"`
+			}{},
+			gitconfig.UnsupportedTypeError,
+		},
+		{
+			struct {
+				D time.Duration `gitconfig:"test.duration"`
+			}{1500 * time.Millisecond},
+			gitconfig.InvalidDurationError,
+		},
+		{
+			struct {
+				N string `gitconfig:"name"`
+			}{},
+			gitconfig.InvalidKeyError,
+		},
+		{
+			struct {
+				M map[string]string `gitconfig:"test"`
+			}{map[string]string{"invalid name": "value"}},
+			gitconfig.InvalidKeyError,
+		},
+	} {
+		_, _err := gitconfig.Marshal(_test.v)
+		if _error, _ok := _err.(*gitconfig.PropertyError); _ok {
+			_err = _error.Err
+		}
+		if _err != _test.err {
+			t.Fatalf(
+				"%d: unexpected error; expected %v, got %v",
+				_i, _test.err, _err,
+			)
+		}
+	}
+
+	// ensure properties without a value are written as a name alone
+	_bytes, _err := gitconfig.Marshal(struct {
+		P gitconfig.Property `gitconfig:"core.bare"`
+		N gitconfig.Property `gitconfig:"core.missing"`
+	}{P: gitconfig.NewPropertyWithoutValue("core.bare")})
+	if _err != nil {
+		t.Fatalf("unexpected error from Marshal(): %s", _err.Error())
+	} else if string(_bytes) != "[core]\n\tbare\n" {
+		t.Fatalf("unexpected configuration: %q", string(_bytes))
+	}
+} // TestMarshalErrors()
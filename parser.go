@@ -0,0 +1,367 @@
+package gitconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError is returned when the contents of a git configuration file do
+// not conform to the git configuration syntax.
+type ParseError struct {
+	// File is the path of the configuration file being parsed, or the empty
+	// string if the configuration was not read from a file.
+	File string
+
+	// Line is the line number of the invalid configuration line.
+	Line int
+}
+
+// Error returns the string representation of the parse error, matching the
+// error reported by git.
+func (e *ParseError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("bad config line %d", e.Line)
+	}
+	return fmt.Sprintf("bad config line %d in file %s", e.Line, e.File)
+} // Error()
+
+// token identifies the type of element encountered by the parser
+type token int
+
+const (
+	_SECTION token = iota // a section header, e.g. [remote "origin"]
+	_ENTRY                // a name/value entry, e.g. url = https://...
+)
+
+// element is a section header or entry encountered by the parser
+type element struct {
+	token      token
+	line       int    // line number on which the element starts
+	start      int    // byte offset of the start of the element
+	end        int    // byte offset immediately after the element
+	section    string // the lower-cased section name
+	subsection string // the case-preserved subsection name
+	variable   string // the lower-cased variable name, for entries
+	value      string // the unescaped value, for entries
+	novalue    bool   // true if the entry has no "=" and no value
+}
+
+// name returns the fully qualified property name of the element.
+func (e *element) name() string {
+	_parts := make([]string, 0, 3)
+	if e.section != "" {
+		_parts = append(_parts, e.section)
+	}
+	if e.subsection != "" {
+		_parts = append(_parts, e.subsection)
+	}
+	_parts = append(_parts, e.variable)
+
+	return strings.Join(_parts, ".")
+} // name()
+
+// parser is the native parser for the git configuration file format, as
+// described in https://git-scm.com/docs/git-config#_syntax.
+type parser struct {
+	file       string
+	data       []byte
+	pos        int
+	line       int
+	eof        bool
+	section    string
+	subsection string
+}
+
+// newParser returns a parser for the configuration data, where file is the
+// path of the configuration file used for error reporting.
+func newParser(file string, data []byte) *parser {
+	// skip the UTF-8 byte order mark if present
+	_pos := 0
+	if len(data) >= 3 && data[0] == 0xef && data[1] == 0xbb && data[2] == 0xbf {
+		_pos = 3
+	}
+
+	return &parser{file: file, data: data, pos: _pos, line: 1}
+} // newParser()
+
+// parse parses the configuration data, calling fn for each section header
+// and entry encountered, in file order. parse returns a *ParseError if the
+// configuration data is invalid, or the error returned by fn.
+func (p *parser) parse(fn func(e *element) error) error {
+	_comment := false
+	for {
+		_start := p.pos
+		_c := p.next()
+		if _c == '\n' {
+			if p.eof {
+				return nil
+			}
+			_comment = false
+			continue
+		} else if _comment || isspace(_c) {
+			continue
+		} else if _c == '#' || _c == ';' {
+			_comment = true
+			continue
+		}
+
+		// are we starting a section header?
+		if _c == '[' {
+			_element, _err := p.header(_start)
+			if _err != nil {
+				return _err
+			}
+			if _err = fn(_element); _err != nil {
+				return _err
+			}
+			continue
+		}
+
+		// otherwise, we must have an entry
+		if !isalpha(_c) {
+			return p.error(p.line)
+		}
+		_element, _err := p.entry(_start, _c)
+		if _err != nil {
+			return _err
+		}
+		if _err = fn(_element); _err != nil {
+			return _err
+		}
+	}
+} // parse()
+
+// header parses a section header, where start is the offset of the opening
+// "[" of the header.
+func (p *parser) header(start int) (*element, error) {
+	_line := p.line
+	_section := []byte{}
+	_subsection := []byte{}
+	_extended := false
+	for !_extended {
+		_c := p.next()
+		if p.eof {
+			return nil, p.error(_line)
+		} else if _c == ']' {
+			break
+		} else if isspace(_c) {
+			// we have an extended section header, e.g. [section "name"]
+			for isspace(_c) {
+				if _c == '\n' {
+					return nil, p.error(_line)
+				}
+				_c = p.next()
+			}
+			if _c != '"' {
+				return nil, p.error(_line)
+			}
+
+			// extract the quoted subsection name
+			for {
+				_c = p.next()
+				if _c == '\n' {
+					return nil, p.error(_line)
+				} else if _c == '"' {
+					break
+				} else if _c == '\\' {
+					_c = p.next()
+					if _c == '\n' {
+						return nil, p.error(_line)
+					}
+				}
+				_subsection = append(_subsection, _c)
+			}
+			if p.next() != ']' {
+				return nil, p.error(_line)
+			}
+			_extended = true
+		} else if iskeychar(_c) || _c == '.' {
+			_section = append(_section, tolower(_c))
+		} else {
+			return nil, p.error(_line)
+		}
+	}
+	if len(_section) == 0 {
+		return nil, p.error(_line)
+	}
+
+	// split the deprecated [section.subsection] syntax
+	//		- the subsection name is lower-cased, as it is by git
+	p.section = string(_section)
+	p.subsection = string(_subsection)
+	if !_extended {
+		_parts := strings.SplitN(p.section, ".", 2)
+		if len(_parts) == 2 {
+			p.section, p.subsection = _parts[0], _parts[1]
+		}
+	}
+
+	return &element{
+		token:      _SECTION,
+		line:       _line,
+		start:      start,
+		end:        p.pos,
+		section:    p.section,
+		subsection: p.subsection,
+	}, nil
+} // header()
+
+// entry parses a name/value entry, where start is the offset of the first
+// character c of the variable name.
+func (p *parser) entry(start int, c byte) (*element, error) {
+	_line := p.line
+	_name := []byte{tolower(c)}
+	for {
+		c = p.next()
+		if p.eof || !iskeychar(c) {
+			break
+		}
+		_name = append(_name, tolower(c))
+	}
+	for c == ' ' || c == '\t' {
+		c = p.next()
+	}
+
+	_element := &element{
+		token:      _ENTRY,
+		line:       _line,
+		start:      start,
+		section:    p.section,
+		subsection: p.subsection,
+		variable:   string(_name),
+	}
+
+	// do we have a value?
+	if c == '\n' {
+		_element.novalue = true
+	} else if c != '=' {
+		return nil, p.error(_line)
+	} else {
+		_value, _err := p.value()
+		if _err != nil {
+			return nil, _err
+		}
+		_element.value = _value
+	}
+	_element.end = p.pos
+
+	return _element, nil
+} // entry()
+
+// value parses the value of an entry, following the "=" separator,
+// removing quotes, interpreting escape sequences and line continuations,
+// and discarding trailing comments.
+func (p *parser) value() (string, error) {
+	_value := []byte{}
+	_quote := false
+	_comment := false
+	_space := 0
+	for {
+		_c := p.next()
+		if _c == '\n' {
+			if _quote {
+				// report the line on which the value ended
+				if p.eof {
+					return "", p.error(p.line)
+				}
+				return "", p.error(p.line - 1)
+			}
+			return string(_value), nil
+		} else if _comment {
+			continue
+		} else if isspace(_c) && !_quote {
+			if len(_value) > 0 {
+				_space++
+			}
+			continue
+		} else if !_quote && (_c == ';' || _c == '#') {
+			_comment = true
+			continue
+		}
+
+		// retain whitespace within the value
+		for ; _space > 0; _space-- {
+			_value = append(_value, ' ')
+		}
+
+		switch _c {
+		case '\\':
+			_c = p.next()
+			switch _c {
+			case '\n':
+				continue
+			case 't':
+				_c = '\t'
+			case 'b':
+				_c = '\b'
+			case 'n':
+				_c = '\n'
+			case '\\', '"':
+			default:
+				return "", p.error(p.line)
+			}
+		case '"':
+			_quote = !_quote
+			continue
+		}
+		_value = append(_value, _c)
+	}
+} // value()
+
+// next returns the next character from the configuration data, normalising
+// "\r\n" line endings to "\n". At the end of the data, next returns "\n"
+// and marks the parser as having reached EOF.
+func (p *parser) next() byte {
+	if p.pos >= len(p.data) {
+		p.eof = true
+		return '\n'
+	}
+
+	_c := p.data[p.pos]
+	p.pos++
+	if _c == '\r' && p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		_c = '\n'
+		p.pos++
+	}
+	if _c == '\n' {
+		p.line++
+	}
+
+	return _c
+} // next()
+
+// error returns the ParseError for the given line of the configuration.
+func (p *parser) error(line int) error {
+	return &ParseError{File: p.file, Line: line}
+} // error()
+
+//
+// helper functions
+//
+
+// isspace returns true if c is a whitespace character.
+func isspace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+} // isspace()
+
+// isalpha returns true if c is an ASCII letter.
+func isalpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+} // isalpha()
+
+// iskeychar returns true if c may appear in a section or variable name.
+func iskeychar(c byte) bool {
+	return isalpha(c) || (c >= '0' && c <= '9') || c == '-'
+} // iskeychar()
+
+// tolower returns the lower-case form of the ASCII character c.
+func tolower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+} // tolower()
@@ -0,0 +1,130 @@
+package gitconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+type parsetest struct {
+	content    string
+	properties []string
+}
+
+var (
+	// define the parser tests
+	//		- properties are listed as "name=value" in name order
+	_PARSE = []parsetest{
+		{"", []string{}},
+		{"# comment\n; comment\n\n", []string{}},
+		{"[core]\n\tbare = false\n", []string{"core.bare=false"}},
+		{"[Core]\n\tBare = false\n", []string{"core.bare=false"}},
+		{"[core]\n\tbare\n", []string{"core.bare="}},
+		{"[core]\n\tbare =\n", []string{"core.bare="}},
+		{"[core] bare = true ; comment\n", []string{"core.bare=true"}},
+		{"[core]\nbare = true # comment\n", []string{"core.bare=true"}},
+		{"[core]\r\nbare = true\r\n", []string{"core.bare=true"}},
+		{"\xef\xbb\xbf[core]\nbare = true", []string{"core.bare=true"}},
+		{
+			"[remote \"Origin\"]\n\turl = https://example.com/x.git\n",
+			[]string{"remote.Origin.url=https://example.com/x.git"},
+		},
+		{
+			"[remote \"a\\\"b\\\\c\"]\n\turl = x\n",
+			[]string{"remote.a\"b\\c.url=x"},
+		},
+		{"[Branch.Main]\n\tremote = origin\n", []string{"branch.main.remote=origin"}},
+		{
+			"[url \"https://github.com/\"]\n\tinsteadOf = gh:\n",
+			[]string{"url.https://github.com/.insteadof=gh:"},
+		},
+		{"[a]\nb = x  y\t z  \n", []string{"a.b=x  y  z"}},
+		{"[a]\nb = \"  x ; y \"\n", []string{"a.b=  x ; y "}},
+		{"[a]\nb = x\\ty\\nz\\b\\\\\\\"\n", []string{"a.b=x\ty\nz\b\\\""}},
+		{"[a]\nb = x\\\ny\n", []string{"a.b=xy"}},
+		{"[a]\nb = \"x\\\n y\"\n", []string{"a.b=x y"}},
+		{"[a]\nb = x\"y\"z\n", []string{"a.b=xyz"}},
+		{"[a]\nb = 1\nc = 2\n[d]\ne = 3\n", []string{"a.b=1", "a.c=2", "d.e=3"}},
+		{"a = 1\n", []string{"a=1"}},
+	}
+
+	// define the invalid parser tests, mapping content to the line number
+	// of the expected error
+	_INVALID = map[string]int{
+		"[]\n":                    1,
+		"[core\n":                 1,
+		"[core \"x]\n":            1,
+		"[core \"x\" ]\n":         1,
+		"[core x]\n":              1,
+		"[co_re]\n":               1,
+		"[a]\n1b = 2\n":           2,
+		"[a]\nb c\n":              2,
+		"[a]\nb = \"x\n":          2,
+		"[a]\nb = x\\q\n":         2,
+		"[a]\nb = 1\n\n% x\n":     4,
+		"[a]\nb = 1\\\n\\\nc\"\n": 4,
+	}
+)
+
+func TestParse(t *testing.T) {
+	for _, _test := range _PARSE {
+		_config, _err := gitconfig.NewConfigFromReader(
+			strings.NewReader(_test.content),
+		)
+		if _err != nil {
+			t.Fatalf(
+				"%q: unexpected error from NewConfigFromReader(): %s",
+				_test.content, _err.Error(),
+			)
+		}
+
+		// ensure the properties are as expected
+		_all := _config.All()
+		if len(_all) != len(_test.properties) {
+			t.Fatalf(
+				"%q: unexpected property count; expected %d, got %d",
+				_test.content, len(_test.properties), len(_all),
+			)
+		}
+		for _i, _property := range _all {
+			_got := _property.Name() + "=" + _property.String()
+			if _got != _test.properties[_i] {
+				t.Fatalf(
+					"%q: unexpected property; expected %q, got %q",
+					_test.content, _test.properties[_i], _got,
+				)
+			}
+		}
+	}
+} // TestParse()
+
+func TestParseError(t *testing.T) {
+	for _content, _line := range _INVALID {
+		_config, _err := gitconfig.NewConfigFromReader(
+			strings.NewReader(_content),
+		)
+		if _err == nil {
+			t.Fatalf(
+				"%q: expected error from NewConfigFromReader(); none found",
+				_content,
+			)
+		} else if _config != nil {
+			t.Fatalf("%q: unexpected Config; expected nil", _content)
+		}
+
+		// ensure we have the correct error
+		_error, _ok := _err.(*gitconfig.ParseError)
+		if !_ok {
+			t.Fatalf(
+				"%q: unexpected error type; expected %T, got %T",
+				_content, _error, _err,
+			)
+		} else if _error.Line != _line {
+			t.Fatalf(
+				"%q: unexpected error line; expected %d, got %d",
+				_content, _line, _error.Line,
+			)
+		}
+	}
+} // TestParseError()
@@ -0,0 +1,47 @@
+package gitconfig
+
+import (
+	"os"
+	"os/user"
+	"strings"
+)
+
+// expand returns path with a leading "~/" or "~user/" expanded to the home
+// directory of the current user or the named user, respectively, and a
+// leading "%(prefix)/" expanded to the installation prefix of git, as git
+// does for path values.
+func expand(path string) (string, error) {
+	if strings.HasPrefix(path, "%(prefix)/") {
+		return _PREFIX + strings.TrimPrefix(path, "%(prefix)"), nil
+	} else if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+
+	// extract the user name, if given
+	_name := strings.TrimPrefix(path, "~")
+	_rest := ""
+	if _i := strings.Index(_name, "/"); _i != -1 {
+		_name, _rest = _name[:_i], _name[_i:]
+	}
+
+	// determine the home directory
+	var _home string
+	if _name == "" {
+		_home = os.Getenv("HOME")
+		if _home == "" {
+			_user, _err := user.Current()
+			if _err != nil {
+				return "", _err
+			}
+			_home = _user.HomeDir
+		}
+	} else {
+		_user, _err := user.Lookup(_name)
+		if _err != nil {
+			return "", _err
+		}
+		_home = _user.HomeDir
+	}
+
+	return strings.TrimSuffix(_home, "/") + _rest, nil
+} // expand()
@@ -2,67 +2,248 @@ package gitconfig
 
 import (
 	"errors"
+	"math"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 var (
 	InvalidBooleanError = errors.New("invalid boolean value")
 	InvalidIntegerError = errors.New("invalid integer value")
+	IntegerRangeError   = errors.New("integer value out of range")
+	InvalidPathError    = errors.New("invalid path value")
+	InvalidExpiryError  = errors.New("invalid expiry value")
 
 	// true and false values for boolean properties
 	_True  = true
 	_False = false
 )
 
+var (
+	// ExpireNever is the expiry date of "never" and "false", the Unix epoch,
+	// before which nothing is dated, so nothing expires.
+	ExpireNever = time.Unix(0, 0)
+
+	// ExpireAll is the expiry date of "now" and "all", the latest time that
+	// may be represented, so everything expires, including entries dated in
+	// the future.
+	ExpireAll = time.Unix(math.MaxInt64-62135596800, 999999999)
+)
+
 // Property represents the name/value pair for a configuration property.
 type Property interface {
 	// Name returns the name of the property.
 	Name() string
 
-	// String returns the string representation of the property value.
+	// Key returns the name of the property, separated into its section,
+	// subsection and variable names.
+	Key() Key
+
+	// String returns the string representation of the property value. If
+	// the property has no value, String returns the empty string.
 	String() string
 
-	// Bool returns the boolean value of the property. If the property value
-	// is not a valid boolean, an error will be returned.
+	// HasValue returns false if the property is defined without a value,
+	// such as a bare "name" without "=" in a configuration file, and true
+	// otherwise. A property without a value is a true boolean, while a
+	// property with an empty value is a false boolean.
+	HasValue() bool
+
+	// Bool returns the boolean value of the property, following git's rules:
+	// a property without a value is true, an empty value is false, the
+	// case-insensitive words "true", "yes" and "on" are true, while "false",
+	// "no" and "off" are false, and any other integer value is true if it is
+	// non-zero. If the property value is not a valid boolean, an error will
+	// be returned.
 	Bool() (bool, error)
 
 	// List returns the list representation of the property. List splits the
 	// string representation of the property value at colons ":".
 	List() []string
 
-	// Int returns the integer representation of the property. If the property
-	// value is not a valid integer, an error will be returned.
+	// Int returns the integer representation of the property. Integers are
+	// parsed as for Int64. If the property value is not a valid integer, or
+	// the integer does not fit in an int, an error will be returned.
 	Int() (int, error)
+
+	// Int64 returns the 64-bit integer representation of the property. As
+	// with git, the integer may be given in decimal, in hexadecimal with a
+	// "0x" prefix, or in octal with a leading "0", and may have a
+	// case-insensitive "k", "m" or "g" suffix, scaling the value by 1024,
+	// 1024^2 or 1024^3 respectively. If the property value is not a valid
+	// integer, InvalidIntegerError will be returned, while
+	// IntegerRangeError is returned if the integer does not fit in 64 bits.
+	Int64() (int64, error)
+
+	// Uint64 returns the unsigned 64-bit integer representation of the
+	// property, parsed as for Int64. Negative values are not valid unsigned
+	// integers.
+	Uint64() (uint64, error)
+
+	// Path returns the path representation of the property, expanded as git
+	// does for path values: a leading "~/" or "~user/" is replaced by the
+	// home directory of the current or named user, and a leading
+	// "%(prefix)/" by the installation prefix of git. If the origin file of
+	// the property is known, relative paths are resolved against the
+	// directory of that file. If the property has no value, or the path
+	// cannot be expanded, an error will be returned.
+	Path() (string, error)
+
+	// ExpiryDate returns the date represented by the property, relative to
+	// now, as git does for expiry values such as "gc.reflogExpire". The value
+	// may be an absolute date, or a date relative to now such as
+	// "90.days.ago" or "2.weeks". As with git, "never" and "false" give
+	// ExpireNever, and "now" and "all" give ExpireAll rather than now, so
+	// that entries dated in the future also expire. If the value is not a
+	// valid expiry date, InvalidExpiryError will be returned.
+	ExpiryDate(now time.Time) (time.Time, error)
+
+	// Duration returns the duration represented by the property, given as a
+	// relative date such as "2.weeks" or "90.days.ago", or as a number of
+	// seconds. Months are 30 days, and years are 365 days. If the value is
+	// not a valid duration, InvalidExpiryError will be returned.
+	Duration() (time.Duration, error)
+
+	// BoolOrInt returns the boolean or integer value of the property, as
+	// used by properties such as "merge.log". If the property has no value,
+	// or its value is one of git's boolean words ("true", "yes", "on",
+	// "false", "no", "off" or the empty string), the result is a boolean.
+	// Otherwise, the value must be an integer, parsed as for Int, and an
+	// error will be returned if it is not.
+	BoolOrInt() (BoolOrInt, error)
+
+	// BoolOrString returns the boolean or string value of the property, as
+	// used by properties such as "core.autocrlf" and "pull.rebase". If the
+	// property value is a valid boolean, as for Bool, the result is a
+	// boolean, and otherwise the result is the string value.
+	BoolOrString() BoolOrString
+
+	// Color returns the color represented by the property, given as a git
+	// color specification such as "bold red ul". If the value is not a valid
+	// color specification, InvalidColorError will be returned.
+	Color() (Color, error)
+
+	// Scope returns the scope of the configuration that defines the property,
+	// or UnknownScope if the scope is not known.
+	Scope() Scope
+
+	// File returns the path of the configuration file that defines the
+	// property, including files loaded through include directives. If the
+	// property was not defined by a file, File returns the empty string.
+	File() string
+
+	// Line returns the line number of the property definition within File,
+	// or 0 if the line number is not known.
+	Line() int
+}
+
+// BoolOrInt is the value of a property that may be either a boolean or an
+// integer.
+type BoolOrInt struct {
+	// IsBool is true if the value is a boolean, and false if it is an
+	// integer.
+	IsBool bool
+
+	// Bool is the boolean value, if IsBool is true.
+	Bool bool
+
+	// Int is the integer value. If IsBool is true, Int is 1 for true and 0
+	// for false, as it is for git.
+	Int int
+}
+
+// BoolOrString is the value of a property that may be either a boolean or a
+// string, such as "core.autocrlf", which may be a boolean or "input".
+type BoolOrString struct {
+	// IsBool is true if the value is a boolean, and false if it is a
+	// string.
+	IsBool bool
+
+	// Bool is the boolean value, if IsBool is true.
+	Bool bool
+
+	// String is the string value of the property, which is the empty string
+	// if the property has no value.
+	String string
 }
 
 // property is the implementation of the Property interface.
+//
+// Conversions of the property value are computed on first use and cached,
+// with each cache guarded by a sync.Once, so that properties may be shared
+// safely between goroutines.
 type property struct {
-	name string
-	v    string
-	b    *bool
-	i    *int
-	l    []string
+	name    string
+	v       string
+	novalue bool
+	scope   Scope
+	file    string
+	line    int
+
+	// cached conversions
+	bonce sync.Once
+	b     *bool
+	ionce sync.Once
+	i     int
+	ierr  error
+	lonce sync.Once
+	l     []string
 }
 
 // NewProperty returns a Property instance with the given name and value v.
+// The name is canonicalised as by git, with the section and variable names
+// lower-cased, while the subsection name is preserved. The origin of the
+// returned property is unknown.
 func NewProperty(name, v string) Property {
-	return &property{name: name, v: v}
+	return &property{name: canonical(name), v: v}
 } // NewProperty()
 
+// NewPropertyWithoutValue returns a Property instance with the given name and
+// no value, as given by a bare "name" without "=" in a configuration file.
+// The name is canonicalised as for NewProperty, and the origin of the
+// returned property is unknown.
+func NewPropertyWithoutValue(name string) Property {
+	return &property{name: canonical(name), novalue: true}
+} // NewPropertyWithoutValue()
+
 // Name returns the name of the property.
-func (p property) Name() string { return p.name }
+func (p *property) Name() string { return p.name }
+
+// Key returns the name of the property, separated into its section,
+// subsection and variable names.
+func (p *property) Key() Key { return newKey(p.name) }
 
 // String returns the string representation of the property value.
-func (p property) String() string { return p.v }
+func (p *property) String() string { return p.v }
 
-// Bool returns the boolean value of the property. If the property value
-// is not a valid boolean, Bool returns the InvalidBooleanError.
-func (p property) Bool() (bool, error) {
-	if p.b == nil {
-		p.b = boolean(p.v)
+// HasValue returns false if the property is defined without a value, and
+// true otherwise.
+func (p *property) HasValue() bool { return !p.novalue }
+
+// Scope returns the scope of the configuration that defines the property,
+// or UnknownScope if the scope is not known.
+func (p *property) Scope() Scope { return p.scope }
+
+// File returns the path of the configuration file that defines the property,
+// or the empty string if the property was not defined by a file.
+func (p *property) File() string { return p.file }
+
+// Line returns the line number of the property definition within File, or 0
+// if the line number is not known.
+func (p *property) Line() int { return p.line }
+
+// Bool returns the boolean value of the property, following git's rules. If
+// the property value is not a valid boolean, Bool returns the
+// InvalidBooleanError.
+func (p *property) Bool() (bool, error) {
+	if p.novalue {
+		return true, nil
 	}
 
+	p.bonce.Do(func() { p.b = boolean(p.v) })
 	if p.b == nil {
 		return false, InvalidBooleanError
 	} else {
@@ -72,40 +253,201 @@ func (p property) Bool() (bool, error) {
 
 // List returns the list representation of the property. List splits the
 // string representation of the property value at colons ":".
-func (p property) List() []string {
-	if p.l == nil {
-		p.l = list(p.v)
-	}
+func (p *property) List() []string {
+	p.lonce.Do(func() { p.l = list(p.v) })
+
+	// return a copy of the list, so the cached list cannot be modified
+	_list := make([]string, len(p.l))
+	copy(_list, p.l)
 
-	return p.l
+	return _list
 } // List()
 
-// Int returns the integer representation of the property. If the property
-// value is not a valid integer, Int returns the InvalidIntegerError.
-func (p property) Int() (int, error) {
-	if p.i == nil {
-		p.i = integer(p.v)
+// Int returns the integer representation of the property, parsed as for
+// Int64. If the property value is not a valid integer, Int returns the
+// InvalidIntegerError, while IntegerRangeError is returned if the integer
+// does not fit in an int.
+func (p *property) Int() (int, error) {
+	p.ionce.Do(func() {
+		_int, _err := integer(p.v, strconv.IntSize)
+		p.i, p.ierr = int(_int), _err
+	})
+
+	return p.i, p.ierr
+} // Int()
+
+// Int64 returns the 64-bit integer representation of the property,
+// accepting git's "k", "m" and "g" unit suffixes. If the property value is
+// not a valid integer, Int64 returns the InvalidIntegerError, while
+// IntegerRangeError is returned if the integer does not fit in 64 bits.
+func (p *property) Int64() (int64, error) {
+	return integer(p.v, 64)
+} // Int64()
+
+// Uint64 returns the unsigned 64-bit integer representation of the property,
+// accepting git's "k", "m" and "g" unit suffixes. If the property value is
+// not a valid unsigned integer, Uint64 returns the InvalidIntegerError,
+// while IntegerRangeError is returned if the integer does not fit in 64
+// bits.
+func (p *property) Uint64() (uint64, error) {
+	return unsigned(p.v, 64)
+} // Uint64()
+
+// Path returns the path representation of the property, with "~/",
+// "~user/" and "%(prefix)/" expanded, and relative paths resolved against
+// the directory of the file defining the property, if known. If the
+// property has no value, or the path cannot be expanded, Path returns the
+// InvalidPathError.
+func (p *property) Path() (string, error) {
+	if p.novalue {
+		return "", InvalidPathError
 	}
 
-	if p.i == nil {
-		return 0, InvalidIntegerError
-	} else {
-		return *p.i, nil
+	_path, _err := expand(p.v)
+	if _err != nil {
+		return "", InvalidPathError
+	} else if _path == "" || filepath.IsAbs(_path) || p.file == "" {
+		return _path, nil
+	}
+
+	return filepath.Join(filepath.Dir(p.file), _path), nil
+} // Path()
+
+// ExpiryDate returns the date represented by the property relative to now,
+// as git does for expiry values. If the value is not a valid expiry date,
+// ExpiryDate returns the InvalidExpiryError.
+func (p *property) ExpiryDate(now time.Time) (time.Time, error) {
+	if p.novalue {
+		return time.Time{}, InvalidExpiryError
 	}
-} // Int()
+
+	switch p.v {
+	case "never", "false":
+		return ExpireNever, nil
+	case "now", "all":
+		return ExpireAll, nil
+	}
+
+	_time, _ok := approxidate(p.v, now)
+	if !_ok {
+		return time.Time{}, InvalidExpiryError
+	}
+
+	return _time, nil
+} // ExpiryDate()
+
+// Duration returns the duration represented by the property, given as a
+// relative date or as a number of seconds. If the value is not a valid
+// duration, Duration returns the InvalidExpiryError.
+func (p *property) Duration() (time.Duration, error) {
+	if p.novalue {
+		return 0, InvalidExpiryError
+	}
+
+	_duration, _ok := approxispan(p.v)
+	if !_ok {
+		return 0, InvalidExpiryError
+	}
+
+	return _duration, nil
+} // Duration()
+
+// BoolOrInt returns the boolean or integer value of the property. If the
+// property value is neither a boolean word nor a valid integer, BoolOrInt
+// returns the InvalidIntegerError, or IntegerRangeError if the integer does
+// not fit in an int.
+func (p *property) BoolOrInt() (BoolOrInt, error) {
+	if p.novalue {
+		return BoolOrInt{IsBool: true, Bool: true, Int: 1}, nil
+	} else if _bool := word(p.v); _bool != nil {
+		_result := BoolOrInt{IsBool: true, Bool: *_bool}
+		if *_bool {
+			_result.Int = 1
+		}
+		return _result, nil
+	}
+
+	_int, _err := integer(p.v, strconv.IntSize)
+	if _err != nil {
+		return BoolOrInt{}, _err
+	}
+
+	return BoolOrInt{Int: int(_int)}, nil
+} // BoolOrInt()
+
+// BoolOrString returns the boolean or string value of the property.
+func (p *property) BoolOrString() BoolOrString {
+	if p.novalue {
+		return BoolOrString{IsBool: true, Bool: true}
+	} else if _bool := boolean(p.v); _bool != nil {
+		return BoolOrString{IsBool: true, Bool: *_bool, String: p.v}
+	}
+
+	return BoolOrString{String: p.v}
+} // BoolOrString()
+
+// Color returns the color represented by the property, given as a git color
+// specification. If the value is not a valid color specification, Color
+// returns the InvalidColorError.
+func (p *property) Color() (Color, error) {
+	if p.novalue {
+		return Color{}, InvalidColorError
+	}
+
+	return ParseColor(p.v)
+} // Color()
+
+// ensure property conforms to the Property interface
+var _ Property = &property{}
 
 //
 // helper methods
 //
 
-// boolean converts the given string v into a boolean, if the string represnts
-// a valid boolean value (such as "1", "true", "off", "no", etc). boolean
-// returns nil otherwise.
+// newProperty returns a Property instance with the given name and value v,
+// defined in the given scope at line of file. If novalue is true, the
+// property has no value. The name is canonicalised as for NewProperty.
+func newProperty(
+	name, v string, novalue bool, scope Scope, file string, line int,
+) Property {
+	return &property{
+		name:    canonical(name),
+		v:       v,
+		novalue: novalue,
+		scope:   scope,
+		file:    file,
+		line:    line,
+	}
+} // newProperty()
+
+// boolean converts the given string v into a boolean, if the string
+// represents a valid boolean value, following git's rules: the empty string
+// is false, the case-insensitive words "true", "yes" and "on" are true, while
+// "false", "no" and "off" are false, and any integer is true if it is
+// non-zero. boolean returns nil otherwise.
 func boolean(v string) *bool {
-	switch v {
+	if _bool := word(v); _bool != nil {
+		return _bool
+	}
+
+	// is this an integer value?
+	_int, _err := integer(v, strconv.IntSize)
+	if _err != nil {
+		return nil
+	} else if _int != 0 {
+		return &_True
+	}
+
+	return &_False
+} // boolean()
+
+// word converts the given string v into a boolean, if the string is one of
+// git's boolean words: the empty string is false, the case-insensitive words
+// "true", "yes" and "on" are true, while "false", "no" and "off" are false.
+// word returns nil otherwise.
+func word(v string) *bool {
+	switch strings.ToLower(v) {
 	// true cases
-	case "1":
-		fallthrough
 	case "on":
 		fallthrough
 	case "yes":
@@ -114,7 +456,7 @@ func boolean(v string) *bool {
 		return &_True
 
 	// false cases
-	case "0":
+	case "":
 		fallthrough
 	case "off":
 		fallthrough
@@ -124,9 +466,8 @@ func boolean(v string) *bool {
 		return &_False
 	}
 
-	//	return nil, InvalidBooleanError
 	return nil
-} // boolean()
+} // word()
 
 // list returns the list representation of the value string s. Values are split
 // on colons ":".
@@ -135,14 +476,120 @@ func list(v string) []string {
 	return strings.Split(v, ":")
 } // list()
 
-// integer converts the given string v into an integer, if the string
-// represents a valid integer value. integer returns nil otherwise.
-func integer(v string) *int {
-	// attempt to parse the integer property
-	_int, _err := strconv.Atoi(v)
-	if _err == nil {
-		return &_int
-	} else {
-		return nil
+// integer converts the given string v into a signed integer of the given
+// size in bits, following git's rules. If v is not a valid integer,
+// integer returns InvalidIntegerError, while IntegerRangeError is returned if
+// the integer does not fit in the given number of bits.
+func integer(v string, bits int) (int64, error) {
+	_negative, _magnitude, _err := number(v)
+	if _err != nil {
+		return 0, _err
 	}
+
+	// ensure the value is within range
+	//		- negative values may extend one beyond the positive range
+	_max := uint64(1)<<uint(bits-1) - 1
+	if _negative {
+		if _magnitude > _max+1 {
+			return 0, IntegerRangeError
+		}
+		return -int64(_magnitude-1) - 1, nil
+	} else if _magnitude > _max {
+		return 0, IntegerRangeError
+	}
+
+	return int64(_magnitude), nil
 } // integer()
+
+// unsigned converts the given string v into an unsigned integer of the given
+// size in bits, following git's rules. If v is not a valid unsigned integer,
+// unsigned returns InvalidIntegerError, while IntegerRangeError is returned
+// if the integer does not fit in the given number of bits.
+func unsigned(v string, bits int) (uint64, error) {
+	// git rejects any value containing a minus sign
+	if strings.Contains(v, "-") {
+		return 0, InvalidIntegerError
+	}
+
+	_, _magnitude, _err := number(v)
+	if _err != nil {
+		return 0, _err
+	} else if _magnitude > ^uint64(0)>>uint(64-bits) {
+		return 0, IntegerRangeError
+	}
+
+	return _magnitude, nil
+} // unsigned()
+
+// number parses the integer string v as git does, returning whether the
+// value is negative, and its magnitude. Leading whitespace and a sign are
+// permitted, the base is determined by the prefix of the digits ("0x" for
+// hexadecimal, and "0" for octal), and the digits may be followed by a
+// case-insensitive "k", "m" or "g" unit suffix. If v is not a valid
+// integer, number returns InvalidIntegerError, while IntegerRangeError is
+// returned if the magnitude does not fit in 64 bits.
+func number(v string) (bool, uint64, error) {
+	_v := strings.TrimLeft(v, " \t\n\v\f\r")
+
+	// extract the sign
+	_negative := false
+	if _v != "" && (_v[0] == '-' || _v[0] == '+') {
+		_negative = _v[0] == '-'
+		_v = _v[1:]
+	}
+
+	// determine the base
+	_base := uint64(10)
+	if len(_v) > 2 && _v[0] == '0' && (_v[1] == 'x' || _v[1] == 'X') {
+		_base = 16
+		_v = _v[2:]
+	} else if len(_v) > 1 && _v[0] == '0' {
+		_base = 8
+	}
+
+	// extract the digits
+	_magnitude := uint64(0)
+	_overflow := false
+	_i := 0
+	for ; _i < len(_v); _i++ {
+		_digit := uint64(16)
+		switch _c := _v[_i]; {
+		case _c >= '0' && _c <= '9':
+			_digit = uint64(_c - '0')
+		case _c >= 'a' && _c <= 'f':
+			_digit = uint64(_c-'a') + 10
+		case _c >= 'A' && _c <= 'F':
+			_digit = uint64(_c-'A') + 10
+		}
+		if _digit >= _base {
+			break
+		}
+
+		if _magnitude > (^uint64(0)-_digit)/_base {
+			_overflow = true
+		}
+		_magnitude = _magnitude*_base + _digit
+	}
+	if _i == 0 {
+		return false, 0, InvalidIntegerError
+	}
+
+	// apply the unit suffix
+	_factor := uint64(1)
+	switch strings.ToLower(_v[_i:]) {
+	case "":
+	case "k":
+		_factor = 1 << 10
+	case "m":
+		_factor = 1 << 20
+	case "g":
+		_factor = 1 << 30
+	default:
+		return false, 0, InvalidIntegerError
+	}
+	if _overflow || _magnitude > ^uint64(0)/_factor {
+		return false, 0, IntegerRangeError
+	}
+
+	return _negative, _magnitude * _factor, nil
+} // number()
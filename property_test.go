@@ -1,6 +1,11 @@
 package gitconfig_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/denormal/go-gitconfig"
@@ -32,6 +37,24 @@ func (p *ptest) Test(t *testing.T) {
 		)
 	}
 
+	// ensure the origin of the property is unknown
+	if p.p.Scope() != gitconfig.UnknownScope {
+		t.Errorf(
+			"%q: unexpected scope; expected %s, got %s",
+			p.n, gitconfig.UnknownScope, p.p.Scope(),
+		)
+	} else if p.p.File() != "" {
+		t.Errorf(
+			"%q: unexpected file; expected %q, got %q",
+			p.n, "", p.p.File(),
+		)
+	} else if p.p.Line() != 0 {
+		t.Errorf(
+			"%q: unexpected line; expected %d, got %d",
+			p.n, 0, p.p.Line(),
+		)
+	}
+
 	// do we expect a boolean value?
 	if p.b != nil {
 		_bool, _err := p.p.Bool()
@@ -130,14 +153,15 @@ var (
 	_0     = 0
 	__123  = -123
 	_1234  = 1234
+	_10    = 10
 
 	// define the property tests
 	//		- each test must have a unique name
 	//		- tests are expected to start with X. to assist config_test.go
 	_PROPERTIES = []*ptest{
 		// _P( name, property, bool, int, list )
-		_P("p.a", "-123", nil, &__123, nil),
-		_P("p.b", "1234", nil, &_1234, nil),
+		_P("p.a", "-123", &_true, &__123, nil),
+		_P("p.b", "1234", &_true, &_1234, nil),
 		_P("p.c", ":b:", nil, nil, []string{"", "b", ""}),
 		_P("p.d", "?", nil, nil, nil),
 		_P("p.f", "a:b:c", nil, nil, []string{"a", "b", "c"}),
@@ -149,6 +173,12 @@ var (
 		_P("p.l", "on", &_true, nil, nil),
 		_P("p.m", "yes", &_true, nil, nil),
 		_P("p.n", "true", &_true, nil, nil),
+		_P("p.o", "", &_false, nil, nil),
+		_P("p.p", "TRUE", &_true, nil, nil),
+		_P("p.q", "Off", &_false, nil, nil),
+		_P("p.r", "Yes", &_true, nil, nil),
+		_P("p.s", "10", &_true, &_10, nil),
+		_P("p.t", "truthy", nil, nil, nil),
 	}
 )
 
@@ -159,6 +189,337 @@ func TestProperty(t *testing.T) {
 	}
 } // TestProperty()
 
+func TestPropertyInteger(t *testing.T) {
+	for _v, _expected := range map[string]struct {
+		i    int64
+		err  error
+		u    uint64
+		uerr error
+	}{
+		"0":                    {0, nil, 0, nil},
+		"-0":                   {0, nil, 0, gitconfig.InvalidIntegerError},
+		"42":                   {42, nil, 42, nil},
+		"+42":                  {42, nil, 42, nil},
+		" 42":                  {42, nil, 42, nil},
+		"-42":                  {-42, nil, 0, gitconfig.InvalidIntegerError},
+		"0x1F":                 {31, nil, 31, nil},
+		"010":                  {8, nil, 8, nil},
+		"0k":                   {0, nil, 0, nil},
+		"500k":                 {500 << 10, nil, 500 << 10, nil},
+		"512M":                 {512 << 20, nil, 512 << 20, nil},
+		"1g":                   {1 << 30, nil, 1 << 30, nil},
+		"-2G":                  {-2 << 30, nil, 0, gitconfig.InvalidIntegerError},
+		"9223372036854775807":  {9223372036854775807, nil, 9223372036854775807, nil},
+		"-9223372036854775808": {-9223372036854775808, nil, 0, gitconfig.InvalidIntegerError},
+		"9223372036854775808":  {0, gitconfig.IntegerRangeError, 9223372036854775808, nil},
+		"18446744073709551615": {0, gitconfig.IntegerRangeError, 18446744073709551615, nil},
+		"18446744073709551616": {0, gitconfig.IntegerRangeError, 0, gitconfig.IntegerRangeError},
+		"8589934592g":          {0, gitconfig.IntegerRangeError, 1 << 63, nil},
+		"17179869184G":         {0, gitconfig.IntegerRangeError, 0, gitconfig.IntegerRangeError},
+		"":                     {0, gitconfig.InvalidIntegerError, 0, gitconfig.InvalidIntegerError},
+		"k":                    {0, gitconfig.InvalidIntegerError, 0, gitconfig.InvalidIntegerError},
+		"1kb":                  {0, gitconfig.InvalidIntegerError, 0, gitconfig.InvalidIntegerError},
+		"1t":                   {0, gitconfig.InvalidIntegerError, 0, gitconfig.InvalidIntegerError},
+		"08":                   {0, gitconfig.InvalidIntegerError, 0, gitconfig.InvalidIntegerError},
+		"0x":                   {0, gitconfig.InvalidIntegerError, 0, gitconfig.InvalidIntegerError},
+		"1_000":                {0, gitconfig.InvalidIntegerError, 0, gitconfig.InvalidIntegerError},
+		"42 ":                  {0, gitconfig.InvalidIntegerError, 0, gitconfig.InvalidIntegerError},
+	} {
+		_property := gitconfig.NewProperty("p.integer", _v)
+		_int, _err := _property.Int64()
+		if _err != _expected.err {
+			t.Fatalf(
+				"%q: unexpected Int64() error; expected %v, got %v",
+				_v, _expected.err, _err,
+			)
+		} else if _int != _expected.i {
+			t.Fatalf(
+				"%q: unexpected Int64(); expected %d, got %d",
+				_v, _expected.i, _int,
+			)
+		}
+		_uint, _err := _property.Uint64()
+		if _err != _expected.uerr {
+			t.Fatalf(
+				"%q: unexpected Uint64() error; expected %v, got %v",
+				_v, _expected.uerr, _err,
+			)
+		} else if _uint != _expected.u {
+			t.Fatalf(
+				"%q: unexpected Uint64(); expected %d, got %d",
+				_v, _expected.u, _uint,
+			)
+		}
+
+		// ensure Int() uses the same parser
+		if _expected.err == nil && _expected.i == int64(int(_expected.i)) {
+			_int, _err := _property.Int()
+			if _err != nil {
+				t.Fatalf("%q: unexpected Int() error: %s", _v, _err.Error())
+			} else if int64(_int) != _expected.i {
+				t.Fatalf(
+					"%q: unexpected Int(); expected %d, got %d",
+					_v, _expected.i, _int,
+				)
+			}
+		}
+	}
+} // TestPropertyInteger()
+
+func TestPropertyPath(t *testing.T) {
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+
+	// override the home directory
+	_home := os.Getenv("HOME")
+	defer os.Setenv("HOME", _home)
+	os.Setenv("HOME", "/home/other")
+
+	_path := filepath.Join(_dir, "config")
+	write(t, _path, ""+
+		"[core]\n"+
+		"\texcludesFile = ~/.gitignore\n"+
+		"\thooksPath = hooks\n"+
+		"\ttemplate = /etc/template\n"+
+		"\tprefix = %(prefix)/share/git-core\n"+
+		"\thome = ~\n"+
+		"\tempty =\n"+
+		"\tmissing\n"+
+		"\tunknown = ~unknown-user-name/x\n",
+	)
+	_config, _err := gitconfig.NewConfigFromFile(_path)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewConfigFromFile(): %s", _err.Error())
+	}
+
+	for _name, _expected := range map[string]string{
+		"core.excludesfile": "/home/other/.gitignore",
+		"core.hookspath":    filepath.Join(_dir, "hooks"),
+		"core.template":     "/etc/template",
+		"core.prefix":       "/usr/share/git-core",
+		"core.home":         "/home/other",
+		"core.empty":        "",
+		"core.missing":      "",
+		"core.unknown":      "",
+	} {
+		_got, _err := _config.Get(_name).Path()
+		if _name == "core.missing" || _name == "core.unknown" {
+			if _err != gitconfig.InvalidPathError {
+				t.Fatalf(
+					"%q: unexpected error; expected %v, got %v",
+					_name, gitconfig.InvalidPathError, _err,
+				)
+			}
+			continue
+		} else if _err != nil {
+			t.Fatalf("%q: unexpected error from Path(): %s", _name, _err.Error())
+		} else if _got != _expected {
+			t.Fatalf(
+				"%q: unexpected path; expected %q, got %q",
+				_name, _expected, _got,
+			)
+		}
+	}
+
+	// without an origin, relative paths are not resolved
+	_got, _err := gitconfig.NewProperty("core.hooksPath", "hooks").Path()
+	if _err != nil {
+		t.Fatalf("unexpected error from Path(): %s", _err.Error())
+	} else if _got != "hooks" {
+		t.Fatalf("unexpected path; expected %q, got %q", "hooks", _got)
+	}
+} // TestPropertyPath()
+
+func TestPropertyBoolOrInt(t *testing.T) {
+	for _v, _expected := range map[string]gitconfig.BoolOrInt{
+		"1":     {IsBool: false, Int: 1},
+		"0":     {IsBool: false, Int: 0},
+		"10k":   {IsBool: false, Int: 10240},
+		"yes":   {IsBool: true, Bool: true, Int: 1},
+		"On":    {IsBool: true, Bool: true, Int: 1},
+		"false": {IsBool: true, Bool: false, Int: 0},
+		"":      {IsBool: true, Bool: false, Int: 0},
+	} {
+		_got, _err := gitconfig.NewProperty("merge.log", _v).BoolOrInt()
+		if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _v, _err.Error())
+		} else if _got != _expected {
+			t.Fatalf(
+				"%q: unexpected value; expected %+v, got %+v",
+				_v, _expected, _got,
+			)
+		}
+	}
+
+	// ensure invalid values are rejected
+	_, _err := gitconfig.NewProperty("merge.log", "input").BoolOrInt()
+	if _err != gitconfig.InvalidIntegerError {
+		t.Fatalf(
+			"unexpected error; expected %v, got %v",
+			gitconfig.InvalidIntegerError, _err,
+		)
+	}
+
+	// ensure properties without a value are true
+	_got, _err := gitconfig.NewPropertyWithoutValue("merge.log").BoolOrInt()
+	if _err != nil {
+		t.Fatalf("unexpected error: %s", _err.Error())
+	} else if !_got.IsBool || !_got.Bool {
+		t.Fatalf("unexpected value; expected true, got %+v", _got)
+	}
+} // TestPropertyBoolOrInt()
+
+func TestPropertyBoolOrString(t *testing.T) {
+	for _v, _expected := range map[string]gitconfig.BoolOrString{
+		"input":  {IsBool: false, String: "input"},
+		"merges": {IsBool: false, String: "merges"},
+		"auto":   {IsBool: false, String: "auto"},
+		"1":      {IsBool: true, Bool: true, String: "1"},
+		"10k":    {IsBool: true, Bool: true, String: "10k"},
+		"0":      {IsBool: true, Bool: false, String: "0"},
+		"True":   {IsBool: true, Bool: true, String: "True"},
+		"":       {IsBool: true, Bool: false, String: ""},
+	} {
+		_got := gitconfig.NewProperty("core.autocrlf", _v).BoolOrString()
+		if _got != _expected {
+			t.Fatalf(
+				"%q: unexpected value; expected %+v, got %+v",
+				_v, _expected, _got,
+			)
+		}
+	}
+
+	// ensure properties without a value are true
+	_got := gitconfig.NewPropertyWithoutValue("pull.rebase").BoolOrString()
+	if !_got.IsBool || !_got.Bool {
+		t.Fatalf("unexpected value; expected true, got %+v", _got)
+	}
+} // TestPropertyBoolOrString()
+
+func TestPropertyConcurrency(t *testing.T) {
+	// share each property between goroutines
+	//		- caching is not directly observable, so run with -race to detect
+	//		  unsafe caching of conversions
+	var _wait sync.WaitGroup
+	for _i := 0; _i < 8; _i++ {
+		_wait.Add(1)
+		go func() {
+			defer _wait.Done()
+			for _, _test := range _PROPERTIES {
+				_test.Test(t)
+			}
+		}()
+	}
+	_wait.Wait()
+
+	// ensure repeated and concurrent conversions of a single property all
+	// return the result of the first conversion
+	_property := gitconfig.NewProperty("core.value", "1")
+	_bool, _berr := _property.Bool()
+	_int, _ierr := _property.Int()
+	_items := _property.List()
+	if !_bool || _berr != nil || _int != 1 || _ierr != nil {
+		t.Fatalf(
+			"unexpected conversions; got %v (%v), %d (%v)",
+			_bool, _berr, _int, _ierr,
+		)
+	}
+	for _i := 0; _i < 8; _i++ {
+		_wait.Add(1)
+		go func() {
+			defer _wait.Done()
+			for _j := 0; _j < 100; _j++ {
+				if _b, _err := _property.Bool(); _b != _bool || _err != _berr {
+					t.Errorf(
+						"unexpected boolean; expected %v (%v), got %v (%v)",
+						_bool, _berr, _b, _err,
+					)
+				}
+				if _n, _err := _property.Int(); _n != _int || _err != _ierr {
+					t.Errorf(
+						"unexpected integer; expected %d (%v), got %d (%v)",
+						_int, _ierr, _n, _err,
+					)
+				}
+				if _l := _property.List(); strings.Join(_l, ":") != "1" {
+					t.Errorf("unexpected list; expected %v, got %v", _items, _l)
+				}
+			}
+		}()
+	}
+	_wait.Wait()
+
+	// ensure the cached list cannot be modified through a returned list
+	_property = gitconfig.NewProperty("core.list", "a:b")
+	_list := _property.List()
+	_list[0] = "modified"
+	if _property.List()[0] != "a" {
+		t.Fatalf(
+			"unexpected list item; expected %q, got %q",
+			"a", _property.List()[0],
+		)
+	}
+} // TestPropertyConcurrency()
+
+func TestPropertyWithoutValue(t *testing.T) {
+	// ensure a property without a value is a true boolean
+	_property := gitconfig.NewPropertyWithoutValue("Core.Bare")
+	if _property.Name() != "core.bare" {
+		t.Fatalf(
+			"unexpected name; expected %q, got %q",
+			"core.bare", _property.Name(),
+		)
+	} else if _property.HasValue() {
+		t.Fatal("unexpected value; expected property without value")
+	} else if _property.String() != "" {
+		t.Fatalf("unexpected string; expected %q, got %q", "", _property.String())
+	}
+	_bool, _err := _property.Bool()
+	if _err != nil {
+		t.Fatalf("unexpected error from Bool(): %s", _err.Error())
+	} else if !_bool {
+		t.Fatal("unexpected boolean; expected true, got false")
+	}
+
+	// ensure a property with an empty value is a false boolean
+	_property = gitconfig.NewProperty("core.bare", "")
+	if !_property.HasValue() {
+		t.Fatal("unexpected property without value; expected empty value")
+	}
+	_bool, _err = _property.Bool()
+	if _err != nil {
+		t.Fatalf("unexpected error from Bool(): %s", _err.Error())
+	} else if _bool {
+		t.Fatal("unexpected boolean; expected false, got true")
+	}
+
+	// ensure values are distinguished from no value when parsed
+	_config, _err := gitconfig.NewConfigFromReader(strings.NewReader(
+		"[core]\n\tbare\n\tempty =\n\tvalue = yes\n",
+	))
+	if _err != nil {
+		t.Fatalf("unexpected error from NewConfigFromReader(): %s", _err.Error())
+	}
+	for _name, _expected := range map[string]bool{
+		"core.bare":  false,
+		"core.empty": true,
+		"core.value": true,
+	} {
+		if _config.Get(_name).HasValue() != _expected {
+			t.Fatalf(
+				"%q: unexpected HasValue(); expected %v, got %v",
+				_name, _expected, !_expected,
+			)
+		}
+	}
+} // TestPropertyWithoutValue()
+
 //
 // helper functions
 //
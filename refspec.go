@@ -0,0 +1,386 @@
+package gitconfig
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	InvalidRefspecError = errors.New("invalid refspec")
+)
+
+// Refspec represents a git refspec, such as the
+// "+refs/heads/*:refs/remotes/origin/*" of a "remote.<name>.fetch" property,
+// mapping source references to destination references.
+type Refspec interface {
+	// Source returns the source of the refspec, such as "refs/heads/*". A
+	// source given as "@" is returned as "HEAD".
+	Source() string
+
+	// Destination returns the destination of the refspec, or the empty
+	// string if the refspec has no destination.
+	Destination() string
+
+	// Force returns true if the refspec permits non-fast-forward updates,
+	// as given by a leading "+".
+	Force() bool
+
+	// Negative returns true if the refspec excludes the references matching
+	// its source, as given by a leading "^".
+	Negative() bool
+
+	// Pattern returns true if the source and destination of the refspec
+	// contain a "*" wildcard.
+	Pattern() bool
+
+	// Matching returns true if this is the push refspec ":", which pushes
+	// each branch to the branch of the same name.
+	Matching() bool
+
+	// Exact returns true if the source of the refspec is an object name,
+	// rather than a reference.
+	Exact() bool
+
+	// Match returns true if the reference ref matches the source of the
+	// refspec.
+	Match(ref string) bool
+
+	// Translate returns the destination reference for the source reference
+	// ref, returning false if ref does not match the source of the refspec,
+	// or the refspec has no destination.
+	Translate(ref string) (string, bool)
+
+	// Reverse returns the source reference for the destination reference
+	// ref, returning false if ref does not match the destination of the
+	// refspec.
+	Reverse(ref string) (string, bool)
+
+	// String returns the refspec as it would be given in configuration.
+	String() string
+}
+
+// refspec is the implementation of the Refspec interface.
+type refspec struct {
+	source      string
+	destination *string
+	force       bool
+	negative    bool
+	pattern     bool
+	matching    bool
+	exact       bool
+}
+
+// ParseFetchRefspec returns the Refspec for the fetch refspec v, such as
+// "+refs/heads/*:refs/remotes/origin/*". An empty source fetches "HEAD", and
+// a missing or empty destination does not store the fetched reference. If v
+// is not a valid fetch refspec, ParseFetchRefspec returns
+// InvalidRefspecError.
+func ParseFetchRefspec(v string) (Refspec, error) {
+	return parseRefspec(v, true)
+} // ParseFetchRefspec()
+
+// ParsePushRefspec returns the Refspec for the push refspec v, such as
+// "refs/heads/main:refs/heads/release" or ":refs/heads/obsolete". An empty
+// source deletes the destination reference. If v is not a valid push
+// refspec, ParsePushRefspec returns InvalidRefspecError.
+func ParsePushRefspec(v string) (Refspec, error) {
+	return parseRefspec(v, false)
+} // ParsePushRefspec()
+
+// Source returns the source of the refspec.
+func (r refspec) Source() string { return r.source }
+
+// Destination returns the destination of the refspec, or the empty string if
+// the refspec has no destination.
+func (r refspec) Destination() string {
+	if r.destination == nil {
+		return ""
+	}
+
+	return *r.destination
+} // Destination()
+
+// Force returns true if the refspec permits non-fast-forward updates.
+func (r refspec) Force() bool { return r.force }
+
+// Negative returns true if the refspec excludes the references matching its
+// source.
+func (r refspec) Negative() bool { return r.negative }
+
+// Pattern returns true if the refspec contains a "*" wildcard.
+func (r refspec) Pattern() bool { return r.pattern }
+
+// Matching returns true if this is the push refspec ":".
+func (r refspec) Matching() bool { return r.matching }
+
+// Exact returns true if the source of the refspec is an object name.
+func (r refspec) Exact() bool { return r.exact }
+
+// Match returns true if the reference ref matches the source of the refspec.
+func (r refspec) Match(ref string) bool {
+	if r.matching || r.exact {
+		return false
+	} else if r.pattern {
+		_, _ok := substitute(r.source, ref, "")
+		return _ok
+	}
+
+	return ref == r.source
+} // Match()
+
+// Translate returns the destination reference for the source reference ref.
+func (r refspec) Translate(ref string) (string, bool) {
+	if r.negative || r.matching || r.exact || r.Destination() == "" {
+		return "", false
+	} else if r.pattern {
+		return substitute(r.source, ref, *r.destination)
+	} else if ref != r.source {
+		return "", false
+	}
+
+	return *r.destination, true
+} // Translate()
+
+// Reverse returns the source reference for the destination reference ref.
+func (r refspec) Reverse(ref string) (string, bool) {
+	if r.negative || r.matching || r.exact || r.Destination() == "" {
+		return "", false
+	} else if r.pattern {
+		return substitute(*r.destination, ref, r.source)
+	} else if ref != *r.destination {
+		return "", false
+	}
+
+	return r.source, true
+} // Reverse()
+
+// String returns the refspec as it would be given in configuration.
+func (r refspec) String() string {
+	_string := r.source
+	if r.matching {
+		_string = ":"
+	} else if r.destination != nil {
+		_string += ":" + *r.destination
+	}
+
+	if r.force {
+		return "+" + _string
+	} else if r.negative {
+		return "^" + _string
+	}
+
+	return _string
+} // String()
+
+// ensure refspec conforms to the Refspec interface
+var _ Refspec = &refspec{}
+
+//
+// private functions
+//
+
+// parseRefspec returns the Refspec for the fetch or push refspec v, following
+// the rules applied by git.
+func parseRefspec(v string, fetch bool) (Refspec, error) {
+	_refspec := &refspec{}
+	_v := v
+	if strings.HasPrefix(_v, "+") {
+		_refspec.force = true
+		_v = _v[1:]
+	} else if strings.HasPrefix(_v, "^") {
+		_refspec.negative = true
+		_v = _v[1:]
+	}
+
+	// is this the matching push refspec?
+	if !fetch && _v == ":" {
+		_refspec.matching = true
+		return _refspec, nil
+	}
+
+	// split the source and destination
+	//		- the destination follows the last colon
+	//		- negative refspecs have no destination
+	_source := _v
+	if _i := strings.LastIndex(_v, ":"); _i != -1 {
+		if _refspec.negative {
+			return nil, InvalidRefspecError
+		}
+		_destination := _v[_i+1:]
+		_refspec.destination = &_destination
+		_source = _v[:_i]
+	}
+
+	// source and destination patterns must be given together
+	//		- fetch refspecs with a source pattern require a destination
+	_glob := strings.Contains(_refspec.Destination(), "*")
+	if strings.Contains(_source, "*") {
+		if _refspec.destination != nil && !_glob {
+			return nil, InvalidRefspecError
+		} else if _refspec.destination == nil && !_refspec.negative && fetch {
+			return nil, InvalidRefspecError
+		}
+		_glob = true
+	} else if _glob {
+		return nil, InvalidRefspecError
+	}
+	_refspec.pattern = _glob
+	if _source == "@" {
+		_source = "HEAD"
+	}
+	_refspec.source = _source
+
+	// validate the refspec
+	_valid := false
+	switch {
+	case _refspec.negative:
+		// negative refspecs must be valid references
+		_valid = _source != "" && !oid(_source) && refname(_source, _glob)
+
+	case fetch:
+		// an empty source is "HEAD", and the source may be an object name
+		_refspec.exact = oid(_source)
+		_valid = _source == "" || _refspec.exact || refname(_source, _glob)
+		if _destination := _refspec.Destination(); _destination != "" {
+			_valid = _valid && refname(_destination, _glob)
+		}
+
+	default:
+		// an empty push source deletes the destination, and anything goes
+		// for other sources, which may be revisions
+		_valid = _source == "" || !_glob || refname(_source, _glob)
+		if _refspec.destination == nil {
+			_valid = _valid && refname(_source, _glob)
+		} else {
+			_destination := *_refspec.destination
+			_valid = _valid && _destination != "" && refname(_destination, _glob)
+		}
+	}
+	if !_valid {
+		return nil, InvalidRefspecError
+	}
+
+	return _refspec, nil
+} // parseRefspec()
+
+// refname returns true if name is a valid reference name, as accepted by
+// "git check-ref-format --allow-onelevel". If pattern is true, name may
+// contain a single "*" wildcard.
+func refname(name string, pattern bool) bool {
+	if name == "" || name == "@" || strings.Contains(name, "@{") {
+		return false
+	} else if strings.HasSuffix(name, ".") {
+		return false
+	} else if strings.Count(name, "*") > 1 {
+		return false
+	} else if strings.Contains(name, "*") && !pattern {
+		return false
+	}
+
+	for _, _component := range strings.Split(name, "/") {
+		if _component == "" || strings.HasPrefix(_component, ".") {
+			return false
+		} else if strings.Contains(_component, "..") {
+			return false
+		} else if strings.HasSuffix(_component, ".lock") {
+			return false
+		}
+		for _, _r := range _component {
+			if _r < 0x20 || _r == 0x7f || strings.ContainsRune(" ~^:?[\\", _r) {
+				return false
+			}
+		}
+	}
+
+	return true
+} // refname()
+
+// oid returns true if name is a full hexadecimal object name.
+func oid(name string) bool {
+	if len(name) != 40 && len(name) != 64 {
+		return false
+	}
+	for _, _r := range name {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", _r) {
+			return false
+		}
+	}
+
+	return true
+} // oid()
+
+// substitute matches name against pattern, which contains a single "*"
+// wildcard, and returns value with its wildcard replaced by the portion of
+// name matched by the wildcard of pattern. If name does not match pattern,
+// substitute returns false.
+func substitute(pattern, name, value string) (string, bool) {
+	_i := strings.Index(pattern, "*")
+	_prefix, _suffix := pattern[:_i], pattern[_i+1:]
+	if len(name) < len(_prefix)+len(_suffix) {
+		return "", false
+	} else if !strings.HasPrefix(name, _prefix) {
+		return "", false
+	} else if !strings.HasSuffix(name, _suffix) {
+		return "", false
+	}
+	_match := name[len(_prefix) : len(name)-len(_suffix)]
+
+	return strings.Replace(value, "*", _match, 1), true
+} // substitute()
+
+// query returns the reference that ref is mapped to by the given refspecs,
+// from source to destination, or from destination to source if reverse is
+// true. The first matching refspec is used, and a reference whose source
+// matches a negative refspec is not mapped. If ref is not mapped, query
+// returns false.
+func query(refspecs []Refspec, ref string, reverse bool) (string, bool) {
+	// find the mapping of ref, and the candidate source references
+	_result := ""
+	_sources := []string{ref}
+	if reverse {
+		_sources = nil
+	}
+	if reverse {
+		for _, _refspec := range refspecs {
+			if _source, _ok := _refspec.Reverse(ref); _ok {
+				if _result == "" {
+					_result = _source
+				}
+				_sources = append(_sources, _source)
+			}
+		}
+	} else {
+		_result, _ = translate(refspecs, ref)
+	}
+	if _result == "" {
+		return "", false
+	}
+
+	// the source must not be excluded by a negative refspec
+	for _, _refspec := range refspecs {
+		if !_refspec.Negative() {
+			continue
+		}
+		for _, _source := range _sources {
+			if _refspec.Match(_source) {
+				return "", false
+			}
+		}
+	}
+
+	return _result, true
+} // query()
+
+// translate returns the reference that ref is mapped to by the first of the
+// given refspecs with a matching source, ignoring negative refspecs, as git
+// does when resolving the upstream and push destinations of a branch. If ref
+// is not mapped, translate returns false.
+func translate(refspecs []Refspec, ref string) (string, bool) {
+	for _, _refspec := range refspecs {
+		if _destination, _ok := _refspec.Translate(ref); _ok {
+			return _destination, true
+		}
+	}
+
+	return "", false
+} // translate()
@@ -0,0 +1,241 @@
+package gitconfig_test
+
+import (
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+// the fields of a parsed refspec
+type refspec struct {
+	source      string
+	destination string
+	force       bool
+	negative    bool
+	pattern     bool
+	matching    bool
+	exact       bool
+}
+
+// the object name used by the refspec tests
+var _OID = "0123456789abcdef0123456789abcdef01234567"
+
+func TestRefspecParse(t *testing.T) {
+	for _, _test := range []struct {
+		refspec  string
+		fetch    bool
+		expected *refspec
+	}{
+		// fetch refspecs
+		{
+			"+refs/heads/*:refs/remotes/origin/*", true,
+			&refspec{
+				source:      "refs/heads/*",
+				destination: "refs/remotes/origin/*",
+				force:       true,
+				pattern:     true,
+			},
+		},
+		{
+			"refs/heads/main:refs/remotes/origin/main", true,
+			&refspec{
+				source:      "refs/heads/main",
+				destination: "refs/remotes/origin/main",
+			},
+		},
+		{"^refs/heads/tmp/*", true,
+			&refspec{source: "refs/heads/tmp/*", negative: true, pattern: true},
+		},
+		{"^refs/heads/tmp", true,
+			&refspec{source: "refs/heads/tmp", negative: true},
+		},
+		{_OID + ":refs/heads/fixed", true,
+			&refspec{source: _OID, destination: "refs/heads/fixed", exact: true},
+		},
+		{"main", true, &refspec{source: "main"}},
+		{"main:", true, &refspec{source: "main"}},
+		{"", true, &refspec{}},
+		{"@", true, &refspec{source: "HEAD"}},
+		{"refs/heads/*-release:refs/remotes/origin/*", true,
+			&refspec{
+				source:      "refs/heads/*-release",
+				destination: "refs/remotes/origin/*",
+				pattern:     true,
+			},
+		},
+		{"refs/heads/*", true, nil},
+		{"refs/heads/*:refs/remotes/origin/main", true, nil},
+		{"refs/heads/main:refs/remotes/origin/*", true, nil},
+		{"refs/heads/*/*:refs/remotes/origin/*/*", true, nil},
+		{"^refs/heads/tmp:refs/heads/tmp", true, nil},
+		{"^" + _OID, true, nil},
+		{"^", true, nil},
+		{"refs/heads/a..b", true, nil},
+		{"refs/heads/main:refs/heads/a b", true, nil},
+		{":", true, &refspec{}},
+
+		// push refspecs
+		{":", false, &refspec{matching: true}},
+		{"+:", false, &refspec{force: true, matching: true}},
+		{
+			"refs/heads/main:refs/heads/release", false,
+			&refspec{
+				source:      "refs/heads/main",
+				destination: "refs/heads/release",
+			},
+		},
+		{":refs/heads/obsolete", false,
+			&refspec{destination: "refs/heads/obsolete"},
+		},
+		{"HEAD~1:refs/heads/previous", false,
+			&refspec{source: "HEAD~1", destination: "refs/heads/previous"},
+		},
+		{"refs/heads/*", false,
+			&refspec{source: "refs/heads/*", pattern: true},
+		},
+		{"main", false, &refspec{source: "main"}},
+		{"HEAD~1", false, nil},
+		{"main:", false, nil},
+		{"", false, nil},
+	} {
+		var (
+			_refspec gitconfig.Refspec
+			_err     error
+		)
+		if _test.fetch {
+			_refspec, _err = gitconfig.ParseFetchRefspec(_test.refspec)
+		} else {
+			_refspec, _err = gitconfig.ParsePushRefspec(_test.refspec)
+		}
+
+		// should this refspec be invalid?
+		if _test.expected == nil {
+			if _err != gitconfig.InvalidRefspecError {
+				t.Errorf(
+					"%q: expected InvalidRefspecError, got %v",
+					_test.refspec, _err,
+				)
+			}
+			continue
+		} else if _err != nil {
+			t.Errorf("%q: unexpected error: %s", _test.refspec, _err)
+			continue
+		}
+
+		_got := &refspec{
+			source:      _refspec.Source(),
+			destination: _refspec.Destination(),
+			force:       _refspec.Force(),
+			negative:    _refspec.Negative(),
+			pattern:     _refspec.Pattern(),
+			matching:    _refspec.Matching(),
+			exact:       _refspec.Exact(),
+		}
+		if *_got != *_test.expected {
+			t.Errorf(
+				"%q: refspec mismatch; expected %+v, got %+v",
+				_test.refspec, *_test.expected, *_got,
+			)
+		}
+
+		// the string form of the refspec should be unchanged
+		//		- "@" is parsed as "HEAD"
+		if _test.refspec != "@" && _refspec.String() != _test.refspec {
+			t.Errorf(
+				"%q: string mismatch; got %q",
+				_test.refspec, _refspec.String(),
+			)
+		}
+	}
+} // TestRefspecParse()
+
+func TestRefspecTranslate(t *testing.T) {
+	for _, _test := range []struct {
+		refspec     string
+		source      string
+		destination string
+		match       bool
+	}{
+		{
+			"+refs/heads/*:refs/remotes/origin/*",
+			"refs/heads/main", "refs/remotes/origin/main", true,
+		},
+		{
+			"+refs/heads/*:refs/remotes/origin/*",
+			"refs/heads/feature/x", "refs/remotes/origin/feature/x", true,
+		},
+		{
+			"+refs/heads/*:refs/remotes/origin/*",
+			"refs/tags/v1", "", false,
+		},
+		{
+			"refs/heads/*-release:refs/remotes/origin/*",
+			"refs/heads/1.0-release", "refs/remotes/origin/1.0", true,
+		},
+		{
+			"refs/heads/*-release:refs/remotes/origin/*",
+			"refs/heads/main", "", false,
+		},
+		{
+			"refs/heads/main:refs/remotes/origin/trunk",
+			"refs/heads/main", "refs/remotes/origin/trunk", true,
+		},
+		{
+			"refs/heads/main:refs/remotes/origin/trunk",
+			"refs/heads/mainline", "", false,
+		},
+		{"refs/heads/main", "refs/heads/main", "", true},
+	} {
+		_refspec, _err := gitconfig.ParseFetchRefspec(_test.refspec)
+		if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _test.refspec, _err)
+		}
+
+		// ensure the source is matched
+		if _refspec.Match(_test.source) != _test.match {
+			t.Errorf(
+				"%q: %q match mismatch; expected %v",
+				_test.refspec, _test.source, _test.match,
+			)
+		}
+
+		// ensure the source and destination are translated
+		_destination, _ok := _refspec.Translate(_test.source)
+		if _ok != (_test.destination != "") {
+			t.Errorf(
+				"%q: %q translate mismatch; expected %v, got %v",
+				_test.refspec, _test.source, !_ok, _ok,
+			)
+		} else if _destination != _test.destination {
+			t.Errorf(
+				"%q: %q translate mismatch; expected %q, got %q",
+				_test.refspec, _test.source, _test.destination, _destination,
+			)
+		} else if !_ok {
+			continue
+		}
+
+		_source, _ok := _refspec.Reverse(_test.destination)
+		if !_ok {
+			t.Errorf(
+				"%q: %q reverse unexpectedly failed",
+				_test.refspec, _test.destination,
+			)
+		} else if _source != _test.source {
+			t.Errorf(
+				"%q: %q reverse mismatch; expected %q, got %q",
+				_test.refspec, _test.destination, _test.source, _source,
+			)
+		}
+	}
+
+	// negative refspecs match, but do not translate
+	_refspec, _err := gitconfig.ParseFetchRefspec("^refs/heads/tmp/*")
+	if _err != nil {
+		t.Fatalf("unexpected error: %s", _err)
+	} else if !_refspec.Match("refs/heads/tmp/x") {
+		t.Errorf("%q: expected match", _refspec)
+	} else if _, _ok := _refspec.Translate("refs/heads/tmp/x"); _ok {
+		t.Errorf("%q: unexpected translation", _refspec)
+	}
+} // TestRefspecTranslate()
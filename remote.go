@@ -0,0 +1,307 @@
+package gitconfig
+
+import (
+	"strings"
+)
+
+// Remote represents the configuration of a git remote, given by a
+// "remote.<name>" section.
+type Remote interface {
+	// Name returns the name of the remote.
+	Name() string
+
+	// URLs returns the URLs of the remote, given by "remote.<name>.url",
+	// with "url.<base>.insteadOf" rewrites applied.
+	URLs() []string
+
+	// PushURLs returns the URLs used to push to the remote. These are the
+	// URLs given by "remote.<name>.pushurl", with "url.<base>.insteadOf"
+	// rewrites applied. If the remote has no push URLs, its URLs are
+	// rewritten by "url.<base>.pushInsteadOf", and if none of these
+	// rewrites apply, PushURLs returns the same URLs as URLs.
+	PushURLs() []string
+
+	// Fetch returns the fetch refspecs of the remote, given by
+	// "remote.<name>.fetch".
+	Fetch() []string
+
+	// Push returns the push refspecs of the remote, given by
+	// "remote.<name>.push".
+	Push() []string
+
+	// FetchRefspecs returns the parsed fetch refspecs of the remote. If a
+	// fetch refspec is not valid, FetchRefspecs returns a *PropertyError
+	// with InvalidRefspecError.
+	FetchRefspecs() ([]Refspec, error)
+
+	// PushRefspecs returns the parsed push refspecs of the remote. If a push
+	// refspec is not valid, PushRefspecs returns a *PropertyError with
+	// InvalidRefspecError.
+	PushRefspecs() ([]Refspec, error)
+
+	// TrackingRef returns the remote-tracking reference that the reference
+	// ref of the remote is fetched to, such as "refs/remotes/origin/main"
+	// for "refs/heads/main", as mapped by the first matching fetch refspec.
+	// If ref is not fetched to a remote-tracking reference, or is excluded
+	// by a negative refspec, TrackingRef returns false, as the reference is
+	// not fetched. Invalid fetch refspecs are ignored. Note that git ignores
+	// negative refspecs when resolving the upstream and push destinations of
+	// a branch, as does Branch.
+	TrackingRef(ref string) (string, bool)
+
+	// RemoteRef returns the reference of the remote that is fetched to the
+	// remote-tracking reference ref, reversing TrackingRef. If no reference
+	// of the remote is fetched to ref, RemoteRef returns false. Invalid fetch
+	// refspecs are ignored.
+	RemoteRef(ref string) (string, bool)
+
+	// Mirror returns true if the remote is a mirror, as given by
+	// "remote.<name>.mirror".
+	Mirror() bool
+
+	// Prune returns true if fetching from the remote removes stale
+	// remote-tracking references, as given by "remote.<name>.prune", or by
+	// "fetch.prune" if the remote does not set the property.
+	Prune() bool
+
+	// TagOpt returns the tag option for fetching from the remote, given by
+	// "remote.<name>.tagOpt", such as "--no-tags" or "--tags", or the empty
+	// string if the option is not set.
+	TagOpt() string
+
+	// Promisor returns true if the remote is used to fetch promisor objects,
+	// as given by "remote.<name>.promisor".
+	Promisor() bool
+
+	// PartialCloneFilter returns the filter used when fetching from the
+	// remote, given by "remote.<name>.partialCloneFilter", or the empty
+	// string if the remote has no filter.
+	PartialCloneFilter() string
+}
+
+// remote is the implementation of the Remote interface.
+type remote struct {
+	name     string
+	urls     []string
+	pushurls []string
+	fetch    []string
+	push     []string
+	mirror   bool
+	prune    bool
+	tagopt   string
+	promisor bool
+	filter   string
+}
+
+// Name returns the name of the remote.
+func (r remote) Name() string { return r.name }
+
+// URLs returns the URLs of the remote, with "url.<base>.insteadOf" rewrites
+// applied.
+func (r remote) URLs() []string { return append([]string{}, r.urls...) }
+
+// PushURLs returns the URLs used to push to the remote.
+func (r remote) PushURLs() []string {
+	if len(r.pushurls) == 0 {
+		return r.URLs()
+	}
+
+	return append([]string{}, r.pushurls...)
+} // PushURLs()
+
+// Fetch returns the fetch refspecs of the remote.
+func (r remote) Fetch() []string { return append([]string{}, r.fetch...) }
+
+// Push returns the push refspecs of the remote.
+func (r remote) Push() []string { return append([]string{}, r.push...) }
+
+// FetchRefspecs returns the parsed fetch refspecs of the remote.
+func (r remote) FetchRefspecs() ([]Refspec, error) {
+	return refspecs(r.name, "fetch", r.fetch, ParseFetchRefspec)
+} // FetchRefspecs()
+
+// PushRefspecs returns the parsed push refspecs of the remote.
+func (r remote) PushRefspecs() ([]Refspec, error) {
+	return refspecs(r.name, "push", r.push, ParsePushRefspec)
+} // PushRefspecs()
+
+// TrackingRef returns the remote-tracking reference that the reference ref
+// of the remote is fetched to.
+func (r remote) TrackingRef(ref string) (string, bool) {
+	return query(r.valid(), ref, false)
+} // TrackingRef()
+
+// RemoteRef returns the reference of the remote that is fetched to the
+// remote-tracking reference ref.
+func (r remote) RemoteRef(ref string) (string, bool) {
+	return query(r.valid(), ref, true)
+} // RemoteRef()
+
+// Mirror returns true if the remote is a mirror.
+func (r remote) Mirror() bool { return r.mirror }
+
+// Prune returns true if fetching from the remote removes stale
+// remote-tracking references.
+func (r remote) Prune() bool { return r.prune }
+
+// TagOpt returns the tag option for fetching from the remote.
+func (r remote) TagOpt() string { return r.tagopt }
+
+// Promisor returns true if the remote is used to fetch promisor objects.
+func (r remote) Promisor() bool { return r.promisor }
+
+// PartialCloneFilter returns the filter used when fetching from the remote.
+func (r remote) PartialCloneFilter() string { return r.filter }
+
+// ensure remote conforms to the Remote interface
+var _ Remote = &remote{}
+
+//
+// private methods
+//
+
+// valid returns the valid fetch refspecs of the remote.
+func (r remote) valid() []Refspec {
+	_refspecs := make([]Refspec, 0, len(r.fetch))
+	for _, _fetch := range r.fetch {
+		if _refspec, _err := ParseFetchRefspec(_fetch); _err == nil {
+			_refspecs = append(_refspecs, _refspec)
+		}
+	}
+
+	return _refspecs
+} // valid()
+
+//
+// private functions
+//
+
+// remotes returns the remotes defined by the configuration c, in the order
+// they are first defined. Boolean properties of remotes with invalid values
+// are treated as false.
+func remotes(c Config) []Remote {
+	_remotes := make([]Remote, 0)
+	for _, _name := range c.Subsections("remote") {
+		_remotes = append(_remotes, newRemote(c, _name))
+	}
+
+	return _remotes
+} // remotes()
+
+// newRemote returns the remote with the given name defined by the
+// configuration c, following git's rules for rewriting URLs.
+func newRemote(c Config, name string) *remote {
+	_prefix := "remote." + name + "."
+	_remote := &remote{
+		name:   name,
+		fetch:  values(c.GetAll(_prefix + "fetch")),
+		push:   values(c.GetAll(_prefix + "push")),
+		mirror: flag(c.Get(_prefix + "mirror")),
+		prune:  flag(c.Get("fetch.prune")),
+	}
+	if _prune := c.Get(_prefix + "prune"); _prune != nil {
+		_remote.prune = flag(_prune)
+	}
+	if _tagopt := c.Get(_prefix + "tagopt"); _tagopt != nil {
+		_remote.tagopt = _tagopt.String()
+	}
+	_remote.promisor = flag(c.Get(_prefix + "promisor"))
+	if _filter := c.Get(_prefix + "partialclonefilter"); _filter != nil {
+		_remote.filter = _filter.String()
+	}
+
+	// rewrite the URLs
+	//		- explicit push URLs are rewritten by insteadOf
+	//		- without push URLs, each URL rewritten by pushInsteadOf is a
+	//		  push URL
+	_rewrites := rewrites(c, "insteadof")
+	for _, _url := range values(c.GetAll(_prefix + "pushurl")) {
+		_remote.pushurls = append(_remote.pushurls, rewrite(_url, _rewrites))
+	}
+	_push := len(_remote.pushurls) == 0
+	_pushrewrites := rewrites(c, "pushinsteadof")
+	for _, _url := range values(c.GetAll(_prefix + "url")) {
+		if _push {
+			if _alias := rewrite(_url, _pushrewrites); _alias != _url {
+				_remote.pushurls = append(_remote.pushurls, _alias)
+			}
+		}
+		_remote.urls = append(_remote.urls, rewrite(_url, _rewrites))
+	}
+
+	return _remote
+} // newRemote()
+
+// refspecs returns the refspecs parsed from the values of the
+// "remote.<name>.<variable>" property by the given parse function, returning
+// a *PropertyError for the first invalid refspec.
+func refspecs(
+	name, variable string,
+	values []string,
+	parse func(string) (Refspec, error),
+) ([]Refspec, error) {
+	_refspecs := make([]Refspec, 0, len(values))
+	for _, _value := range values {
+		_refspec, _err := parse(_value)
+		if _err != nil {
+			_name := "remote." + name + "." + variable
+			return nil, &PropertyError{Name: _name, Err: _err}
+		}
+		_refspecs = append(_refspecs, _refspec)
+	}
+
+	return _refspecs, nil
+} // refspecs()
+
+// rewrites returns the URL rewrites of the configuration c given by the
+// "url.<base>.<variable>" properties, mapping each URL prefix to its base.
+// If several bases share a prefix, the first base defined is used, as it is
+// by git.
+func rewrites(c Config, variable string) map[string]string {
+	_rewrites := make(map[string]string)
+	for _, _base := range c.Subsections("url") {
+		for _, _prefix := range c.GetAll("url." + _base + "." + variable) {
+			if _, _ok := _rewrites[_prefix.String()]; !_ok {
+				_rewrites[_prefix.String()] = _base
+			}
+		}
+	}
+
+	return _rewrites
+} // rewrites()
+
+// rewrite returns url rewritten by the longest matching prefix of the given
+// rewrites, or url if no rewrite applies.
+func rewrite(url string, rewrites map[string]string) string {
+	_longest := -1
+	_rewritten := url
+	for _prefix, _base := range rewrites {
+		if len(_prefix) > _longest && strings.HasPrefix(url, _prefix) {
+			_longest = len(_prefix)
+			_rewritten = _base + strings.TrimPrefix(url, _prefix)
+		}
+	}
+
+	return _rewritten
+} // rewrite()
+
+// values returns the string values of the given properties.
+func values(properties []Property) []string {
+	_values := make([]string, 0, len(properties))
+	for _, _property := range properties {
+		_values = append(_values, _property.String())
+	}
+
+	return _values
+} // values()
+
+// flag returns the boolean value of the property p, or false if p is nil or
+// is not a valid boolean.
+func flag(p Property) bool {
+	if p == nil {
+		return false
+	}
+	_bool, _ := p.Bool()
+
+	return _bool
+} // flag()
@@ -0,0 +1,251 @@
+package gitconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+// the local configuration of the remote tests
+var _REMOTES = `
+[fetch]
+	prune = true
+[url "git@github.com:"]
+	insteadOf = gh:
+	pushInsteadOf = https://github.com/
+[url "https://mirror.example.com/"]
+	insteadOf = https://github.com/
+[url "https://mirror.example.com/special/"]
+	insteadOf = https://github.com/special/
+[url "https://first.example/"]
+	insteadOf = first:
+[url "https://second.example/"]
+	insteadOf = first:
+[remote "origin"]
+	url = https://github.com/user/project.git
+	url = https://github.com/special/project.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+	fetch = ^refs/heads/tmp/*
+	tagOpt = --no-tags
+[remote "upstream"]
+	url = gh:upstream/project.git
+	pushurl = gh:user/project.git
+	pushurl = ssh://example.com/project.git
+	push = refs/heads/main:refs/heads/main
+	prune = false
+	promisor = true
+	partialCloneFilter = blob:none
+[remote "tied"]
+	url = first:x.git
+[remote "backup"]
+	url = /srv/backup.git
+	fetch = refs/heads/*
+	mirror = true
+	prune = maybe
+`
+
+func TestRemotes(t *testing.T) {
+	_dir := repository(t, _REMOTES)
+	defer os.RemoveAll(_dir)
+
+	_config, _err := gitconfig.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("%q: unexpected error from NewWithPath: %s", _dir, _err)
+	}
+
+	// ensure the remotes are returned in the order they are defined
+	_remotes := _config.Remotes()
+	_names := make([]string, 0, len(_remotes))
+	for _, _remote := range _remotes {
+		_names = append(_names, _remote.Name())
+	}
+	_expected := []string{"origin", "upstream", "tied", "backup"}
+	if !reflect.DeepEqual(_names, _expected) {
+		t.Fatalf("remotes mismatch; expected %v, got %v", _expected, _names)
+	}
+
+	for _, _test := range []struct {
+		name     string
+		urls     []string
+		pushurls []string
+		fetch    []string
+		push     []string
+		mirror   bool
+		prune    bool
+		tagopt   string
+		promisor bool
+		filter   string
+	}{
+		{
+			"origin",
+			[]string{
+				"https://mirror.example.com/user/project.git",
+				"https://mirror.example.com/special/project.git",
+			},
+			[]string{
+				"git@github.com:user/project.git",
+				"git@github.com:special/project.git",
+			},
+			[]string{
+				"+refs/heads/*:refs/remotes/origin/*",
+				"^refs/heads/tmp/*",
+			},
+			[]string{},
+			false, true, "--no-tags", false, "",
+		},
+		{
+			"upstream",
+			[]string{"git@github.com:upstream/project.git"},
+			[]string{
+				"git@github.com:user/project.git",
+				"ssh://example.com/project.git",
+			},
+			[]string{},
+			[]string{"refs/heads/main:refs/heads/main"},
+			false, false, "", true, "blob:none",
+		},
+		{
+			"tied",
+			[]string{"https://first.example/x.git"},
+			[]string{"https://first.example/x.git"},
+			[]string{},
+			[]string{},
+			false, true, "", false, "",
+		},
+		{
+			"backup",
+			[]string{"/srv/backup.git"},
+			[]string{"/srv/backup.git"},
+			[]string{"refs/heads/*"},
+			[]string{},
+			true, false, "", false, "",
+		},
+	} {
+		_remote := _config.Remote(_test.name)
+		if _remote == nil {
+			t.Errorf("%s: unexpected nil remote", _test.name)
+			continue
+		}
+
+		for _, _check := range []struct {
+			property string
+			expected interface{}
+			got      interface{}
+		}{
+			{"URLs", _test.urls, _remote.URLs()},
+			{"PushURLs", _test.pushurls, _remote.PushURLs()},
+			{"Fetch", _test.fetch, _remote.Fetch()},
+			{"Push", _test.push, _remote.Push()},
+			{"Mirror", _test.mirror, _remote.Mirror()},
+			{"Prune", _test.prune, _remote.Prune()},
+			{"TagOpt", _test.tagopt, _remote.TagOpt()},
+			{"Promisor", _test.promisor, _remote.Promisor()},
+			{"PartialCloneFilter", _test.filter, _remote.PartialCloneFilter()},
+		} {
+			if !reflect.DeepEqual(_check.got, _check.expected) {
+				t.Errorf(
+					"%s: %s mismatch; expected %v, got %v",
+					_test.name, _check.property, _check.expected, _check.got,
+				)
+			}
+		}
+	}
+
+	// ensure references are mapped by the fetch refspecs
+	//		- references excluded by negative refspecs are not mapped
+	_origin := _config.Remote("origin")
+	for _, _test := range []struct {
+		ref      string
+		tracking string
+	}{
+		{"refs/heads/main", "refs/remotes/origin/main"},
+		{"refs/heads/feature/x", "refs/remotes/origin/feature/x"},
+		{"refs/heads/tmp/x", ""},
+		{"refs/tags/v1", ""},
+	} {
+		_tracking, _ok := _origin.TrackingRef(_test.ref)
+		if _ok != (_test.tracking != "") || _tracking != _test.tracking {
+			t.Errorf(
+				"%s: tracking mismatch; expected %q, got %q",
+				_test.ref, _test.tracking, _tracking,
+			)
+		}
+
+		// and the tracking reference maps back to the remote reference
+		if !strings.HasPrefix(_test.ref, "refs/heads/") {
+			continue
+		}
+		_tracking = "refs/remotes/origin/" + _test.ref[len("refs/heads/"):]
+		_ref, _ok := _origin.RemoteRef(_tracking)
+		if _test.tracking == "" {
+			if _ok {
+				t.Errorf("%s: unexpected remote ref %q", _tracking, _ref)
+			}
+		} else if !_ok || _ref != _test.ref {
+			t.Errorf(
+				"%s: remote ref mismatch; expected %q, got %q",
+				_tracking, _test.ref, _ref,
+			)
+		}
+	}
+
+	// ensure the refspecs are parsed
+	if _refspecs, _err := _origin.FetchRefspecs(); _err != nil {
+		t.Errorf("origin: unexpected fetch refspecs error: %s", _err)
+	} else if len(_refspecs) != 2 || !_refspecs[1].Negative() {
+		t.Errorf("origin: unexpected fetch refspecs %v", _refspecs)
+	}
+	_upstream := _config.Remote("upstream")
+	if _refspecs, _err := _upstream.PushRefspecs(); _err != nil {
+		t.Errorf("upstream: unexpected push refspecs error: %s", _err)
+	} else if len(_refspecs) != 1 {
+		t.Errorf("upstream: unexpected push refspecs %v", _refspecs)
+	}
+	_, _err = _config.Remote("backup").FetchRefspecs()
+	if _err == nil {
+		t.Errorf("backup: expected fetch refspecs error")
+	} else if _error, _ok := _err.(*gitconfig.PropertyError); !_ok {
+		t.Errorf("backup: expected PropertyError, got %v", _err)
+	} else if _error.Err != gitconfig.InvalidRefspecError {
+		t.Errorf("backup: expected InvalidRefspecError, got %v", _error.Err)
+	}
+
+	// ensure undefined remotes are nil
+	if _remote := _config.Remote("missing"); _remote != nil {
+		t.Errorf("missing: expected nil remote, got %v", _remote)
+	}
+} // TestRemotes()
+
+//
+// helper functions
+//
+
+// repository creates a repository in a new temporary directory, with the
+// given local configuration, returning the path of the temporary directory.
+func repository(t *testing.T, config string) string {
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	_dir, _ = filepath.EvalSymlinks(_dir)
+
+	_gitdir := filepath.Join(_dir, ".git")
+	write(t, filepath.Join(_gitdir, "HEAD"), "ref: refs/heads/main\n")
+	write(t, filepath.Join(_gitdir, "config"), config)
+	for _, _name := range []string{"objects", "refs"} {
+		_err = os.MkdirAll(filepath.Join(_gitdir, _name), 0755)
+		if _err != nil {
+			t.Fatalf("unable to create %q: %s", _name, _err.Error())
+		}
+	}
+
+	return _dir
+} // repository()
@@ -0,0 +1,120 @@
+package gitconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// repository describes the location of a git working copy and its git
+// directories, as determined without using the "git" executable
+type repository struct {
+	root      string // the root directory of the working copy
+	gitdir    string // the git directory of the working copy
+	commondir string // the git directory shared by all worktrees
+}
+
+// newRepository returns the repository containing path, searching path and
+// its parent directories for a ".git" directory or file. If path is not
+// within a git working copy, newRepository returns nil.
+func newRepository(path string) (*repository, error) {
+	_path, _err := filepath.Abs(path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	for {
+		_gitdir, _err := gitdir(_path)
+		if _err != nil {
+			return nil, _err
+		} else if _gitdir != "" {
+			_commondir, _err := commondir(_gitdir)
+			if _err != nil {
+				return nil, _err
+			}
+			return &repository{_path, _gitdir, _commondir}, nil
+		}
+
+		// move to the parent directory
+		_parent := filepath.Dir(_path)
+		if _parent == _path {
+			return nil, nil
+		}
+		_path = _parent
+	}
+} // newRepository()
+
+// head returns the short name of the branch currently checked out in the
+// repository, or the empty string if HEAD is detached or cannot be read.
+func (r *repository) head() string {
+	_head, _err := ioutil.ReadFile(filepath.Join(r.gitdir, "HEAD"))
+	if _err != nil {
+		return ""
+	}
+
+	_ref := strings.TrimSpace(string(_head))
+	if !strings.HasPrefix(_ref, "ref:") {
+		return ""
+	}
+	_ref = strings.TrimSpace(strings.TrimPrefix(_ref, "ref:"))
+
+	return strings.TrimPrefix(_ref, "refs/heads/")
+} // head()
+
+//
+// helper functions
+//
+
+// gitdir returns the git directory for the working copy with the root
+// directory root, or the empty string if root is not the root of a working
+// copy. Linked worktrees and submodules use a ".git" file of the form
+// "gitdir: <path>" to identify their git directory.
+func gitdir(root string) (string, error) {
+	_path := filepath.Join(root, ".git")
+	_info, _err := os.Stat(_path)
+	if _err != nil {
+		if os.IsNotExist(_err) {
+			return "", nil
+		}
+		return "", _err
+	} else if _info.IsDir() {
+		return _path, nil
+	}
+
+	// we have a .git file, so extract the path to the git directory
+	_data, _err := ioutil.ReadFile(_path)
+	if _err != nil {
+		return "", _err
+	}
+	_line := strings.TrimSpace(string(_data))
+	if !strings.HasPrefix(_line, "gitdir:") {
+		return "", nil
+	}
+	_gitdir := strings.TrimSpace(strings.TrimPrefix(_line, "gitdir:"))
+	if !filepath.IsAbs(_gitdir) {
+		_gitdir = filepath.Join(root, _gitdir)
+	}
+
+	return filepath.Clean(_gitdir), nil
+} // gitdir()
+
+// commondir returns the common git directory for the git directory gitdir.
+// The git directory of a linked worktree identifies the common directory
+// through its "commondir" file; otherwise the common directory is gitdir.
+func commondir(gitdir string) (string, error) {
+	_data, _err := ioutil.ReadFile(filepath.Join(gitdir, "commondir"))
+	if _err != nil {
+		if os.IsNotExist(_err) {
+			return gitdir, nil
+		}
+		return "", _err
+	}
+
+	_commondir := strings.TrimSpace(string(_data))
+	if !filepath.IsAbs(_commondir) {
+		_commondir = filepath.Join(gitdir, _commondir)
+	}
+
+	return filepath.Clean(_commondir), nil
+} // commondir()
@@ -0,0 +1,53 @@
+package gitconfig
+
+// Scope identifies the source of a block of git configuration.
+type Scope int
+
+const (
+	// UnknownScope is the scope of configuration of unknown origin.
+	UnknownScope Scope = iota
+
+	// SystemScope is the system-wide configuration, as used by
+	// "git config --system".
+	SystemScope
+
+	// GlobalScope is the configuration of the current user, as used by
+	// "git config --global".
+	GlobalScope
+
+	// LocalScope is the configuration of a repository, as used by
+	// "git config --local".
+	LocalScope
+
+	// WorktreeScope is the configuration of a repository working tree,
+	// as used by "git config --worktree".
+	WorktreeScope
+
+	// FileScope is the configuration of an explicit file, as used by
+	// "git config --file".
+	FileScope
+
+	// CommandScope is the configuration given on the command line or in the
+	// environment, as used by "git -c".
+	CommandScope
+)
+
+// String returns the name of the scope.
+func (s Scope) String() string {
+	switch s {
+	case SystemScope:
+		return "system"
+	case GlobalScope:
+		return "global"
+	case LocalScope:
+		return "local"
+	case WorktreeScope:
+		return "worktree"
+	case FileScope:
+		return "file"
+	case CommandScope:
+		return "command"
+	}
+
+	return "unknown"
+} // String()
@@ -0,0 +1,27 @@
+package gitconfig_test
+
+import (
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+func TestScope(t *testing.T) {
+	for _scope, _expected := range map[gitconfig.Scope]string{
+		gitconfig.UnknownScope:  "unknown",
+		gitconfig.SystemScope:   "system",
+		gitconfig.GlobalScope:   "global",
+		gitconfig.LocalScope:    "local",
+		gitconfig.WorktreeScope: "worktree",
+		gitconfig.FileScope:     "file",
+		gitconfig.CommandScope:  "command",
+		gitconfig.Scope(-1):     "unknown",
+	} {
+		if _scope.String() != _expected {
+			t.Fatalf(
+				"unexpected scope name; expected %q, got %q",
+				_expected, _scope.String(),
+			)
+		}
+	}
+} // TestScope()
@@ -0,0 +1,352 @@
+package gitconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	InvalidUnmarshalError = errors.New("unmarshal requires a non-nil struct pointer")
+	UnsupportedTypeError  = errors.New("unsupported field type")
+)
+
+// PropertyError records the failure to convert the named property.
+type PropertyError struct {
+	Name string // the name of the property
+	Err  error  // the conversion error
+}
+
+// Error returns the string representation of the property error.
+func (e *PropertyError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Err.Error())
+} // Error()
+
+// UnmarshalError records every property that Unmarshal was unable to
+// convert.
+type UnmarshalError struct {
+	Errors []*PropertyError
+}
+
+// Error returns the string representation of the unmarshal error, listing
+// each property error in turn.
+func (e *UnmarshalError) Error() string {
+	_errors := make([]string, 0, len(e.Errors))
+	for _, _error := range e.Errors {
+		_errors = append(_errors, _error.Error())
+	}
+
+	return "unable to unmarshal " + strings.Join(_errors, "; ")
+} // Error()
+
+// the types with dedicated conversions
+var (
+	_DURATION       = reflect.TypeOf(time.Duration(0))
+	_COLOR          = reflect.TypeOf(Color{})
+	_BOOL_OR_INT    = reflect.TypeOf(BoolOrInt{})
+	_BOOL_OR_STRING = reflect.TypeOf(BoolOrString{})
+	_PROPERTY       = reflect.TypeOf((*Property)(nil)).Elem()
+)
+
+// Unmarshal stores the configuration c in the struct pointed to by v. Each
+// field of the struct to be set is tagged with the name of its property,
+// such as `gitconfig:"user.name"`; untagged fields, and fields tagged "-",
+// are ignored. Fields are converted using the typed accessors of Property:
+//
+//	string                           String, or Path with the "path" option
+//	bool                             Bool
+//	int, int8, ..., uint64           Int64 or Uint64
+//	time.Duration                    Duration
+//	Color, BoolOrInt, BoolOrString   Color, BoolOrInt and BoolOrString
+//	Property                         the property itself
+//
+// Pointer fields are set only if the property is defined. Slice fields are
+// set to every value of a multi-valued property, while other fields take
+// the last value defined. A nested struct field is tagged with the name of
+// a section, such as `gitconfig:"core"`, and the tags of its own fields are
+// the names of variables within that section. A map field with string keys
+// and struct values is tagged with a section name, such as
+// `gitconfig:"remote"`, and has an entry for each subsection of that
+// section. A map field with string keys and other values has an entry for
+// each variable of the tagged section or subsection.
+//
+// The "default" tag option gives the value of a property that is not
+// defined, such as `gitconfig:"core.autocrlf,default=input"`. The default
+// option must be the last option of the tag.
+//
+// If v is not a non-nil pointer to a struct, Unmarshal returns
+// InvalidUnmarshalError. If any property cannot be converted, Unmarshal
+// continues, and returns an UnmarshalError listing each property that could
+// not be converted.
+func Unmarshal(c Config, v interface{}) error {
+	_value := reflect.ValueOf(v)
+	if _value.Kind() != reflect.Ptr || _value.IsNil() {
+		return InvalidUnmarshalError
+	} else if _value.Elem().Kind() != reflect.Struct {
+		return InvalidUnmarshalError
+	}
+
+	_unmarshaler := &unmarshaler{config: c}
+	_unmarshaler.structure("", _value.Elem())
+	if len(_unmarshaler.errors) != 0 {
+		return &UnmarshalError{Errors: _unmarshaler.errors}
+	}
+
+	return nil
+} // Unmarshal()
+
+//
+// private types
+//
+
+// unmarshaler stores configuration in Go values, recording every conversion
+// error encountered.
+type unmarshaler struct {
+	config Config
+	errors []*PropertyError
+}
+
+// tag represents the parsed "gitconfig" tag of a struct field.
+type tag struct {
+	name      string
+	path      bool
+	omitempty bool
+	defaults  *string
+}
+
+//
+// private methods
+//
+
+// structure stores the configuration in the fields of the struct v, whose
+// field tags are relative to prefix.
+func (u *unmarshaler) structure(prefix string, v reflect.Value) {
+	_type := v.Type()
+	for _i := 0; _i < _type.NumField(); _i++ {
+		_field := _type.Field(_i)
+		_tag, _ok := parseTag(_field.Tag.Get("gitconfig"))
+		if !_ok || _field.PkgPath != "" {
+			continue
+		}
+		_name := _tag.name
+		if prefix != "" {
+			_name = prefix + "." + _name
+		}
+		_value := v.Field(_i)
+
+		switch {
+		case _value.Kind() == reflect.Struct && !converted(_value.Type()):
+			u.structure(_name, _value)
+
+		case _value.Kind() == reflect.Map:
+			u.mapping(_name, _tag, _value)
+
+		case _value.Kind() == reflect.Slice:
+			u.slice(_name, _tag, _value)
+
+		default:
+			_property := u.config.Get(_name)
+			if _property == nil {
+				if _tag.defaults == nil {
+					continue
+				}
+				_property = NewProperty(_name, *_tag.defaults)
+			}
+			u.convert(_property, _tag, _value)
+		}
+	}
+} // structure()
+
+// mapping stores the configuration of the section or subsection name in
+// the map v. Maps of structs have an entry for each subsection of the
+// section name, while other maps have an entry for each variable.
+func (u *unmarshaler) mapping(name string, t tag, v reflect.Value) {
+	_type := v.Type()
+	if _type.Key().Kind() != reflect.String {
+		u.fail(name, UnsupportedTypeError)
+		return
+	}
+
+	// is this a map of subsections?
+	//		- subsections may be given as structs, or pointers to structs
+	_elem := _type.Elem()
+	_struct := _elem
+	if _struct.Kind() == reflect.Ptr {
+		_struct = _struct.Elem()
+	}
+	if _struct.Kind() == reflect.Struct && !converted(_struct) {
+		for _, _subsection := range u.config.Subsections(name) {
+			_value := reflect.New(_struct)
+			u.structure(name+"."+_subsection, _value.Elem())
+			if _elem.Kind() == reflect.Ptr {
+				u.set(v, _subsection, _value)
+			} else {
+				u.set(v, _subsection, _value.Elem())
+			}
+		}
+		return
+	}
+
+	// otherwise, this is a map of variables
+	_section, _subsection := name, ""
+	if _i := strings.Index(name, "."); _i != -1 {
+		_section, _subsection = name[:_i], name[_i+1:]
+	}
+	_config := u.config.Section(_section, _subsection)
+	for _, _property := range _config.All() {
+		_value := reflect.New(_elem).Elem()
+		if u.convert(_property, t, _value) {
+			u.set(v, _property.Key().Variable(), _value)
+		}
+	}
+} // mapping()
+
+// slice stores every value of the named property in the slice v.
+func (u *unmarshaler) slice(name string, t tag, v reflect.Value) {
+	_properties := u.config.GetAll(name)
+	if len(_properties) == 0 {
+		if t.defaults == nil {
+			return
+		}
+		_properties = []Property{NewProperty(name, *t.defaults)}
+	}
+
+	_slice := reflect.MakeSlice(v.Type(), 0, len(_properties))
+	for _, _property := range _properties {
+		_value := reflect.New(v.Type().Elem()).Elem()
+		if u.convert(_property, t, _value) {
+			_slice = reflect.Append(_slice, _value)
+		}
+	}
+	v.Set(_slice)
+} // slice()
+
+// set sets the entry key of the map v to value, creating the map if
+// required.
+func (u *unmarshaler) set(v reflect.Value, key string, value reflect.Value) {
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), value)
+} // set()
+
+// convert stores the value of the property p in v, using the typed
+// accessors of Property, returning false if the conversion fails. If the
+// conversion fails, v is left unchanged.
+func (u *unmarshaler) convert(p Property, t tag, v reflect.Value) bool {
+	var (
+		_result interface{}
+		_err    error
+	)
+	switch _type := v.Type(); {
+	case _type == _PROPERTY:
+		_result = p
+	case _type == _DURATION:
+		_result, _err = p.Duration()
+	case _type == _COLOR:
+		_result, _err = p.Color()
+	case _type == _BOOL_OR_INT:
+		_result, _err = p.BoolOrInt()
+	case _type == _BOOL_OR_STRING:
+		_result = p.BoolOrString()
+
+	default:
+		switch v.Kind() {
+		case reflect.String:
+			_result = p.String()
+			if t.path {
+				_result, _err = p.Path()
+			}
+		case reflect.Bool:
+			_result, _err = p.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Int64:
+			var _int int64
+			_int, _err = p.Int64()
+			if _err == nil && v.OverflowInt(_int) {
+				_err = IntegerRangeError
+			}
+			_result = _int
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64:
+			var _uint uint64
+			_uint, _err = p.Uint64()
+			if _err == nil && v.OverflowUint(_uint) {
+				_err = IntegerRangeError
+			}
+			_result = _uint
+		case reflect.Ptr:
+			_value := reflect.New(_type.Elem())
+			if !u.convert(p, t, _value.Elem()) {
+				return false
+			}
+			_result = _value.Interface()
+		default:
+			_err = UnsupportedTypeError
+		}
+	}
+
+	if _err != nil {
+		u.fail(p.Name(), _err)
+		return false
+	}
+
+	// store the result
+	//		- the result may be of a type other than that of v, such as
+	//		  an int64 for an int field, or a string for a named string type
+	v.Set(reflect.ValueOf(_result).Convert(v.Type()))
+	return true
+} // convert()
+
+// fail records the conversion error err for the named property.
+func (u *unmarshaler) fail(name string, err error) {
+	u.errors = append(u.errors, &PropertyError{Name: name, Err: err})
+} // fail()
+
+//
+// private functions
+//
+
+// parseTag parses the "gitconfig" struct tag t, returning false if the
+// field should be ignored.
+func parseTag(t string) (tag, bool) {
+	if t == "" || t == "-" {
+		return tag{}, false
+	}
+
+	// extract the options
+	//		- the default option may contain commas, so must be last
+	_parts := strings.SplitN(t, ",", 2)
+	_tag := tag{name: _parts[0]}
+	for len(_parts) == 2 {
+		_parts = strings.SplitN(_parts[1], ",", 2)
+		switch {
+		case _parts[0] == "path":
+			_tag.path = true
+		case _parts[0] == "omitempty":
+			_tag.omitempty = true
+		case strings.HasPrefix(_parts[0], "default="):
+			_default := strings.TrimPrefix(_parts[0], "default=")
+			if len(_parts) == 2 {
+				_default += "," + _parts[1]
+			}
+			_tag.defaults = &_default
+			_parts = _parts[:1]
+		}
+	}
+
+	return _tag, _tag.name != ""
+} // parseTag()
+
+// converted returns true if values of type t are converted directly from a
+// single property, rather than being composed of several properties.
+func converted(t reflect.Type) bool {
+	switch t {
+	case _COLOR, _BOOL_OR_INT, _BOOL_OR_STRING:
+		return true
+	}
+
+	return false
+} // converted()
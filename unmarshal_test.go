@@ -0,0 +1,182 @@
+package gitconfig_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denormal/go-gitconfig"
+)
+
+type remote struct {
+	URL    string   `gitconfig:"url"`
+	Fetch  []string `gitconfig:"fetch"`
+	Prune  *bool    `gitconfig:"prune"`
+	TagOpt string   `gitconfig:"tagOpt,default=--tags"`
+}
+
+type settings struct {
+	Name    string `gitconfig:"user.name"`
+	Email   string `gitconfig:"user.email,default=nobody@example.com"`
+	Ignored string
+	Skipped string `gitconfig:"-"`
+	Core    struct {
+		Bare        bool                   `gitconfig:"bare"`
+		AutoCRLF    gitconfig.BoolOrString `gitconfig:"autocrlf"`
+		Threshold   int64                  `gitconfig:"bigFileThreshold"`
+		Compression int                    `gitconfig:"compression,default=-1"`
+		Hooks       string                 `gitconfig:"hooksPath,path"`
+		Editor      *string                `gitconfig:"editor"`
+	} `gitconfig:"core"`
+	Remotes   map[string]remote   `gitconfig:"remote"`
+	Aliases   map[string]string   `gitconfig:"alias"`
+	Branches  map[string]*remote  `gitconfig:"branch"`
+	Expire    time.Duration       `gitconfig:"gc.pruneExpire"`
+	Color     gitconfig.Color     `gitconfig:"color.diff.meta"`
+	Log       gitconfig.BoolOrInt `gitconfig:"merge.log"`
+	Helper    gitconfig.Property  `gitconfig:"credential.helper"`
+	Helpers   []string            `gitconfig:"credential.helper"`
+	Separator string              `gitconfig:"test.separator,default=a,b"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	_config, _err := gitconfig.NewConfigFromReader(strings.NewReader("" +
+		"[user]\n" +
+		"\tname = A. N. Other\n" +
+		"[core]\n" +
+		"\tbare\n" +
+		"\tautocrlf = input\n" +
+		"\tbigFileThreshold = 512m\n" +
+		"\thooksPath = /hooks\n" +
+		"[remote \"origin\"]\n" +
+		"\turl = https://example.com/x.git\n" +
+		"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+		"\tfetch = +refs/tags/*:refs/tags/*\n" +
+		"\tprune = false\n" +
+		"[remote \"Upstream\"]\n" +
+		"\turl = https://example.com/y.git\n" +
+		"\ttagOpt = --no-tags\n" +
+		"[branch \"main\"]\n" +
+		"\tprune = true\n" +
+		"[alias]\n" +
+		"\tco = checkout\n" +
+		"\tst = status\n" +
+		"[gc]\n" +
+		"\tpruneExpire = 2.weeks.ago\n" +
+		"[color \"diff\"]\n" +
+		"\tmeta = bold yellow\n" +
+		"[merge]\n" +
+		"\tlog = 20\n" +
+		"[credential]\n" +
+		"\thelper = cache\n" +
+		"\thelper = store\n",
+	))
+	if _err != nil {
+		t.Fatalf("unexpected error from NewConfigFromReader(): %s", _err.Error())
+	}
+
+	_settings := settings{Ignored: "ignored", Skipped: "skipped"}
+	_err = gitconfig.Unmarshal(_config, &_settings)
+	if _err != nil {
+		t.Fatalf("unexpected error from Unmarshal(): %s", _err.Error())
+	}
+
+	// ensure the fields are set as expected
+	for _name, _test := range map[string][2]interface{}{
+		"user.name":         {_settings.Name, "A. N. Other"},
+		"user.email":        {_settings.Email, "nobody@example.com"},
+		"ignored":           {_settings.Ignored, "ignored"},
+		"skipped":           {_settings.Skipped, "skipped"},
+		"core.bare":         {_settings.Core.Bare, true},
+		"core.autocrlf":     {_settings.Core.AutoCRLF.String, "input"},
+		"core.threshold":    {_settings.Core.Threshold, int64(512 << 20)},
+		"core.compression":  {_settings.Core.Compression, -1},
+		"core.hookspath":    {_settings.Core.Hooks, "/hooks"},
+		"core.editor":       {_settings.Core.Editor == nil, true},
+		"remotes":           {len(_settings.Remotes), 2},
+		"origin.url":        {_settings.Remotes["origin"].URL, "https://example.com/x.git"},
+		"origin.fetch":      {len(_settings.Remotes["origin"].Fetch), 2},
+		"origin.prune":      {*_settings.Remotes["origin"].Prune, false},
+		"origin.tagopt":     {_settings.Remotes["origin"].TagOpt, "--tags"},
+		"upstream.prune":    {_settings.Remotes["Upstream"].Prune == nil, true},
+		"upstream.tagopt":   {_settings.Remotes["Upstream"].TagOpt, "--no-tags"},
+		"aliases":           {len(_settings.Aliases), 2},
+		"alias.co":          {_settings.Aliases["co"], "checkout"},
+		"branches":          {len(_settings.Branches), 1},
+		"main.prune":        {*_settings.Branches["main"].Prune, true},
+		"gc.pruneexpire":    {_settings.Expire, 14 * 24 * time.Hour},
+		"color.diff.meta":   {_settings.Color.ANSI(), "\033[1;33m"},
+		"merge.log":         {_settings.Log.Int, 20},
+		"credential.helper": {_settings.Helper.String(), "store"},
+		"helpers":           {strings.Join(_settings.Helpers, ","), "cache,store"},
+		"test.separator":    {_settings.Separator, "a,b"},
+	} {
+		if _test[0] != _test[1] {
+			t.Fatalf(
+				"%s: unexpected value; expected %v, got %v",
+				_name, _test[1], _test[0],
+			)
+		}
+	}
+} // TestUnmarshal()
+
+func TestUnmarshalErrors(t *testing.T) {
+	_config := gitconfig.NewConfig([]gitconfig.Property{
+		gitconfig.NewProperty("core.bare", "maybe"),
+		gitconfig.NewProperty("core.compression", "lots"),
+		gitconfig.NewProperty("core.small", "300"),
+		gitconfig.NewProperty("core.unsigned", "-1"),
+		gitconfig.NewProperty("core.name", "valid"),
+		gitconfig.NewProperty("core.unsupported", "1.5"),
+	})
+
+	// ensure every conversion error is reported
+	var _settings struct {
+		Bare        bool    `gitconfig:"core.bare"`
+		Compression int     `gitconfig:"core.compression"`
+		Small       int8    `gitconfig:"core.small"`
+		Unsigned    uint    `gitconfig:"core.unsigned"`
+		Name        string  `gitconfig:"core.name"`
+		Unsupported float64 `gitconfig:"core.unsupported"`
+	}
+	_err := gitconfig.Unmarshal(_config, &_settings)
+	_unmarshal, _ok := _err.(*gitconfig.UnmarshalError)
+	if !_ok {
+		t.Fatalf("unexpected error; expected %T, got %v", _unmarshal, _err)
+	}
+	_expected := []gitconfig.PropertyError{
+		{Name: "core.bare", Err: gitconfig.InvalidBooleanError},
+		{Name: "core.compression", Err: gitconfig.InvalidIntegerError},
+		{Name: "core.small", Err: gitconfig.IntegerRangeError},
+		{Name: "core.unsigned", Err: gitconfig.InvalidIntegerError},
+		{Name: "core.unsupported", Err: gitconfig.UnsupportedTypeError},
+	}
+	if len(_unmarshal.Errors) != len(_expected) {
+		t.Fatalf("unexpected errors: %s", _unmarshal.Error())
+	}
+	for _i, _error := range _unmarshal.Errors {
+		if *_error != _expected[_i] {
+			t.Fatalf(
+				"unexpected error; expected %q, got %q",
+				_expected[_i].Error(), _error.Error(),
+			)
+		}
+	}
+	if _settings.Name != "valid" {
+		t.Fatalf(
+			"unexpected value; expected %q, got %q",
+			"valid", _settings.Name,
+		)
+	}
+
+	// ensure invalid targets are rejected
+	for _, _target := range []interface{}{nil, _settings, new(string)} {
+		_err := gitconfig.Unmarshal(_config, _target)
+		if _err != gitconfig.InvalidUnmarshalError {
+			t.Fatalf(
+				"unexpected error; expected %v, got %v",
+				gitconfig.InvalidUnmarshalError, _err,
+			)
+		}
+	}
+} // TestUnmarshalErrors()
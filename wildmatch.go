@@ -0,0 +1,137 @@
+package gitconfig
+
+import (
+	"strings"
+)
+
+// wildmatch returns true if text matches the glob pattern, following the
+// rules of git's wildmatch() for path names: "*" and "?" do not match "/",
+// while "**" between slashes (or at the start or end of the pattern)
+// matches any number of directories. If fold is true, the match is
+// case-insensitive.
+func wildmatch(pattern, text string, fold bool) bool {
+	if fold {
+		pattern = strings.ToLower(pattern)
+		text = strings.ToLower(text)
+	}
+
+	return wmatch(pattern, 0, text)
+} // wildmatch()
+
+//
+// helper functions
+//
+
+// wmatch returns true if text matches the pattern p from the offset pi.
+func wmatch(p string, pi int, text string) bool {
+	for pi < len(p) {
+		switch p[pi] {
+		case '\\':
+			// match the escaped character literally
+			pi++
+			if pi == len(p) || text == "" || text[0] != p[pi] {
+				return false
+			}
+		case '?':
+			if text == "" || text[0] == '/' {
+				return false
+			}
+		case '[':
+			if text == "" || text[0] == '/' {
+				return false
+			}
+			_next, _ok := class(p, pi+1, text[0])
+			if !_ok {
+				return false
+			}
+			pi = _next - 1
+		case '*':
+			_start := pi
+			for pi < len(p) && p[pi] == '*' {
+				pi++
+			}
+
+			// is this a "**" matching across directories?
+			if pi-_start > 1 &&
+				(_start == 0 || p[_start-1] == '/') &&
+				(pi == len(p) || p[pi] == '/') {
+				if pi == len(p) {
+					return true
+				}
+
+				// "**/" matches zero or more leading directories
+				if wmatch(p, pi+1, text) {
+					return true
+				}
+				for _i := 0; _i < len(text); _i++ {
+					if text[_i] == '/' && wmatch(p, pi+1, text[_i+1:]) {
+						return true
+					}
+				}
+				return false
+			}
+
+			// "*" matches anything other than "/"
+			for _i := 0; _i <= len(text); _i++ {
+				if wmatch(p, pi, text[_i:]) {
+					return true
+				} else if _i < len(text) && text[_i] == '/' {
+					break
+				}
+			}
+			return false
+		default:
+			if text == "" || text[0] != p[pi] {
+				return false
+			}
+		}
+
+		pi++
+		text = text[1:]
+	}
+
+	return text == ""
+} // wmatch()
+
+// class returns the offset immediately after the character class starting
+// at offset pi of the pattern p (following the opening "["), and whether
+// the character c is matched by the class. If the class is not terminated,
+// class returns false.
+func class(p string, pi int, c byte) (int, bool) {
+	_negate := false
+	if pi < len(p) && (p[pi] == '!' || p[pi] == '^') {
+		_negate = true
+		pi++
+	}
+
+	_matched := false
+	for _first := true; pi < len(p); _first = false {
+		_c := p[pi]
+		if _c == ']' && !_first {
+			return pi + 1, _matched != _negate
+		} else if _c == '\\' && pi+1 < len(p) {
+			pi++
+			_c = p[pi]
+		}
+
+		// do we have a range?
+		if pi+2 < len(p) && p[pi+1] == '-' && p[pi+2] != ']' {
+			_end := p[pi+2]
+			if _end == '\\' && pi+3 < len(p) {
+				pi++
+				_end = p[pi+2]
+			}
+			if c >= _c && c <= _end {
+				_matched = true
+			}
+			pi += 3
+		} else {
+			if c == _c {
+				_matched = true
+			}
+			pi++
+		}
+	}
+
+	return pi, false
+} // class()
@@ -0,0 +1,270 @@
+package gitconfig
+
+import (
+	"errors"
+	"os"
+
+	"github.com/denormal/go-gittools"
+)
+
+var (
+	InvalidScopeError = errors.New("invalid configuration scope")
+)
+
+// Writer is the interface for modifying git configuration of a
+// particular scope.
+type Writer interface {
+	// Scope returns the scope of the configuration modified by this Writer.
+	Scope() Scope
+
+	// Set sets the property with the given name to value, replacing all
+	// existing values of the property.
+	Set(name, value string) error
+
+	// Add adds value to the property with the given name, retaining any
+	// existing values of the property.
+	Add(name, value string) error
+
+	// Unset removes the property with the given name. If the property has
+	// multiple values, or the property is not set, an error is returned.
+	Unset(name string) error
+
+	// UnsetAll removes all values of the property with the given name. If
+	// the property is not set, an error is returned.
+	UnsetAll(name string) error
+
+	// RenameSection renames the section old to new, where sections are
+	// named either "section" or "section.subsection".
+	RenameSection(old, new string) error
+
+	// RemoveSection removes the section with the given name, including all
+	// of its properties.
+	RemoveSection(name string) error
+}
+
+// writer is the implementation of the Writer interface using "git config"
+type writer struct {
+	scope Scope
+	path  string
+}
+
+// file is the implementation of the Writer interface for configuration
+// files, modifying the file natively using a Document
+type file struct {
+	scope Scope
+	path  string
+}
+
+// NewWriter returns a Writer for modifying the git configuration of the
+// given scope. For LocalScope and WorktreeScope, path identifies the
+// repository, and if path is "", the current working directory of the
+// process will be used. For FileScope, path is the configuration file to
+// modify. path is ignored for SystemScope and GlobalScope. If scope is
+// not a writable scope, or FileScope is requested without a path,
+// InvalidScopeError is returned.
+//
+// Modifications are made using the "git" executable. If git is not
+// installed, the configuration file is modified natively, preserving the
+// formatting of the file. For scopes other than FileScope, the file is the
+// highest priority file of the scope given by Files, and if Files fails,
+// its error is returned. If the scope has no configuration file, such as
+// when GIT_CONFIG_NOSYSTEM is set, MissingGitError is returned.
+func NewWriter(scope Scope, path string) (Writer, error) {
+	switch scope {
+	case SystemScope, GlobalScope:
+		path = ""
+	case LocalScope, WorktreeScope:
+	case FileScope:
+		if path == "" {
+			return nil, InvalidScopeError
+		} else if !gittools.HasGit() {
+			return NewFileWriter(path)
+		}
+		return &writer{scope, path}, nil
+	default:
+		return nil, InvalidScopeError
+	}
+
+	// without git, modify the configuration file of the scope natively
+	if !gittools.HasGit() {
+		_target, _err := target(scope, path)
+		if _err != nil {
+			return nil, _err
+		}
+		return &file{scope, _target}, nil
+	}
+
+	return &writer{scope, path}, nil
+} // NewWriter()
+
+// NewFileWriter returns a Writer for modifying the configuration file at
+// path natively, without using git, preserving the formatting of the file.
+// If the file does not exist, it is created when first modified. If path is
+// "", InvalidScopeError is returned.
+func NewFileWriter(path string) (Writer, error) {
+	if path == "" {
+		return nil, InvalidScopeError
+	}
+
+	return &file{FileScope, path}, nil
+} // NewFileWriter()
+
+// Scope returns the scope of the configuration modified by this Writer.
+func (w writer) Scope() Scope { return w.scope }
+
+// Set sets the property with the given name to value, replacing all
+// existing values of the property.
+func (w writer) Set(name, value string) error {
+	return w.run("--replace-all", "--", name, value)
+} // Set()
+
+// Add adds value to the property with the given name, retaining any
+// existing values of the property.
+func (w writer) Add(name, value string) error {
+	return w.run("--add", "--", name, value)
+} // Add()
+
+// Unset removes the property with the given name. If the property has
+// multiple values, or the property is not set, an error is returned.
+func (w writer) Unset(name string) error {
+	return w.run("--unset", "--", name)
+} // Unset()
+
+// UnsetAll removes all values of the property with the given name. If the
+// property is not set, an error is returned.
+func (w writer) UnsetAll(name string) error {
+	return w.run("--unset-all", "--", name)
+} // UnsetAll()
+
+// RenameSection renames the section old to new, where sections are named
+// either "section" or "section.subsection".
+func (w writer) RenameSection(old, new string) error {
+	return w.run("--rename-section", "--", old, new)
+} // RenameSection()
+
+// RemoveSection removes the section with the given name, including all of
+// its properties.
+func (w writer) RemoveSection(name string) error {
+	return w.run("--remove-section", "--", name)
+} // RemoveSection()
+
+//
+// private methods
+//
+
+// run executes "git config" for the scope of this writer, with the given
+// arguments.
+func (w writer) run(args ...string) error {
+	// select the scope of the configuration to modify
+	_args := []string{"config"}
+	_path := w.path
+	switch w.scope {
+	case SystemScope:
+		_args = append(_args, "--system")
+	case GlobalScope:
+		_args = append(_args, "--global")
+	case LocalScope:
+		_args = append(_args, "--local")
+	case WorktreeScope:
+		_args = append(_args, "--worktree")
+	case FileScope:
+		_args = append(_args, "--file", w.path)
+		_path = ""
+	}
+	_args = append(_args, args...)
+
+	_, _err := gittools.RunInPath(_path, _args...)
+	return _err
+} // run()
+
+// Scope returns the scope of the configuration modified by this Writer.
+func (f file) Scope() Scope { return f.scope }
+
+// Set sets the property with the given name to value, replacing all
+// existing values of the property.
+func (f file) Set(name, value string) error {
+	return f.edit(func(d Document) error { return d.Set(name, value) })
+} // Set()
+
+// Add adds value to the property with the given name, retaining any
+// existing values of the property.
+func (f file) Add(name, value string) error {
+	return f.edit(func(d Document) error { return d.Add(name, value) })
+} // Add()
+
+// Unset removes the property with the given name. If the property has
+// multiple values, or the property is not set, an error is returned.
+func (f file) Unset(name string) error {
+	return f.edit(func(d Document) error { return d.Unset(name) })
+} // Unset()
+
+// UnsetAll removes all values of the property with the given name. If the
+// property is not set, an error is returned.
+func (f file) UnsetAll(name string) error {
+	return f.edit(func(d Document) error { return d.UnsetAll(name) })
+} // UnsetAll()
+
+// RenameSection renames the section old to new, where sections are named
+// either "section" or "section.subsection".
+func (f file) RenameSection(old, new string) error {
+	return f.edit(func(d Document) error { return d.RenameSection(old, new) })
+} // RenameSection()
+
+// RemoveSection removes the section with the given name, including all of
+// its properties.
+func (f file) RemoveSection(name string) error {
+	return f.edit(func(d Document) error { return d.RemoveSection(name) })
+} // RemoveSection()
+
+// edit loads the configuration file as a Document, applies fn to the
+// Document, and saves the result. If the file does not exist, it is
+// created.
+func (f file) edit(fn func(d Document) error) error {
+	_document, _err := NewDocumentFromFile(f.path)
+	if os.IsNotExist(_err) {
+		_document, _err = newDocument(f.path, nil)
+	}
+	if _err != nil {
+		return _err
+	}
+
+	if _err = fn(_document); _err != nil {
+		return _err
+	}
+
+	return _document.Save()
+} // edit()
+
+// ensure writer and file conform to the Writer interface
+var _ Writer = &writer{}
+var _ Writer = &file{}
+
+//
+// private functions
+//
+
+// target returns the configuration file to modify for the given scope when
+// git is not installed, being the last, and so highest priority, of the
+// files given by Files. As with git, the global configuration is written to
+// "~/.gitconfig", unless only the XDG configuration file exists. If the
+// scope has no configuration file, MissingGitError is returned.
+func target(scope Scope, path string) (string, error) {
+	_files, _err := Files(scope, path)
+	if _err != nil {
+		return "", _err
+	} else if len(_files) == 0 {
+		return "", MissingGitError
+	}
+
+	// prefer the last file that exists, or the last file if none exist
+	_target := _files[len(_files)-1]
+	if _, _err := os.Stat(_target); os.IsNotExist(_err) {
+		for _, _file := range _files[:len(_files)-1] {
+			if _, _err := os.Stat(_file); _err == nil {
+				_target = _file
+			}
+		}
+	}
+
+	return _target, nil
+} // target()
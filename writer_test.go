@@ -0,0 +1,363 @@
+package gitconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+	"github.com/denormal/go-gittools"
+)
+
+func TestNewWriter(t *testing.T) {
+	// ensure invalid scopes are rejected
+	for _scope, _path := range map[gitconfig.Scope]string{
+		gitconfig.UnknownScope: "",
+		gitconfig.FileScope:    "",
+		gitconfig.Scope(-1):    "config",
+	} {
+		_writer, _err := gitconfig.NewWriter(_scope, _path)
+		if _err != gitconfig.InvalidScopeError {
+			t.Fatalf(
+				"%s: unexpected error; expected %q, got %v",
+				_scope, gitconfig.InvalidScopeError, _err,
+			)
+		} else if _writer != nil {
+			t.Fatalf("%s: unexpected Writer; expected nil", _scope)
+		}
+	}
+
+	// ensure valid scopes are accepted
+	_dir := repository(t, "")
+	defer os.RemoveAll(_dir)
+	for _, _scope := range []gitconfig.Scope{
+		gitconfig.SystemScope,
+		gitconfig.GlobalScope,
+		gitconfig.LocalScope,
+		gitconfig.WorktreeScope,
+	} {
+		_writer, _err := gitconfig.NewWriter(_scope, _dir)
+		if _err != nil {
+			t.Fatalf(
+				"%s: unexpected error from NewWriter(): %s",
+				_scope, _err.Error(),
+			)
+		} else if _writer.Scope() != _scope {
+			t.Fatalf(
+				"unexpected scope; expected %s, got %s",
+				_scope, _writer.Scope(),
+			)
+		}
+	}
+} // TestNewWriter()
+
+func TestWriter(t *testing.T) {
+	// do we have git installed?
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+
+	// write to a configuration file
+	_path := filepath.Join(_dir, "config")
+	_writer, _err := gitconfig.NewWriter(gitconfig.FileScope, _path)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWriter(): %s", _err.Error())
+	}
+	writer(t, _writer, func() gitconfig.Config {
+		_config, _err := gitconfig.NewConfigFromFile(_path)
+		if _err != nil {
+			t.Fatalf(
+				"unexpected error from NewConfigFromFile(): %s",
+				_err.Error(),
+			)
+		}
+		return _config
+	})
+
+	// write to the local configuration of a repository
+	_, _err = gittools.RunInPath(_dir, "init", "-q")
+	if _err != nil {
+		t.Fatalf("unable to create repository: %s", _err.Error())
+	}
+	_writer, _err = gitconfig.NewWriter(gitconfig.LocalScope, _dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWriter(): %s", _err.Error())
+	}
+	writer(t, _writer, func() gitconfig.Config {
+		_config, _err := gitconfig.NewLocalConfig(_dir)
+		if _err != nil {
+			t.Fatalf(
+				"unexpected error from NewLocalConfig(): %s",
+				_err.Error(),
+			)
+		}
+		return _config
+	})
+} // TestWriter()
+
+func TestFileWriter(t *testing.T) {
+	// ensure a path is required
+	_writer, _err := gitconfig.NewFileWriter("")
+	if _err != gitconfig.InvalidScopeError {
+		t.Fatalf(
+			"unexpected error; expected %q, got %v",
+			gitconfig.InvalidScopeError, _err,
+		)
+	} else if _writer != nil {
+		t.Fatalf("unexpected Writer; expected nil")
+	}
+
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf(
+			"unable to create temporary directory: %s",
+			_err.Error(),
+		)
+	}
+	defer os.RemoveAll(_dir)
+
+	// write to a configuration file that does not exist
+	_path := filepath.Join(_dir, "config")
+	_writer, _err = gitconfig.NewFileWriter(_path)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewFileWriter(): %s", _err.Error())
+	} else if _writer.Scope() != gitconfig.FileScope {
+		t.Fatalf(
+			"unexpected scope; expected %s, got %s",
+			gitconfig.FileScope, _writer.Scope(),
+		)
+	}
+	writer(t, _writer, func() gitconfig.Config {
+		_config, _err := gitconfig.NewConfigFromFile(_path)
+		if _err != nil {
+			t.Fatalf(
+				"unexpected error from NewConfigFromFile(): %s",
+				_err.Error(),
+			)
+		}
+		return _config
+	})
+
+	// ensure the formatting of an existing file is preserved
+	_content := "# user settings\n[user]\n    name = Someone ; inline\n"
+	write(t, _path, _content)
+	if _err = _writer.Set("user.email", "me@example.com"); _err != nil {
+		t.Fatalf("unexpected error from Set(): %s", _err.Error())
+	}
+	_data, _err := ioutil.ReadFile(_path)
+	if _err != nil {
+		t.Fatalf("unable to read configuration file: %s", _err.Error())
+	}
+	_expected := _content + "    email = me@example.com\n"
+	if string(_data) != _expected {
+		t.Fatalf(
+			"unexpected file content; expected %q, got %q",
+			_expected, string(_data),
+		)
+	}
+} // TestFileWriter()
+
+func TestNativeWriter(t *testing.T) {
+	_dir := repository(t, "[core]\n\tbare = false\n")
+	defer os.RemoveAll(_dir)
+
+	// hide git, and the configuration files of the current user
+	defer unset(_DISCOVERY...)()
+	defer unset("PATH")()
+	if gittools.HasGit() {
+		t.Skip("unable to hide git")
+	}
+	_home := filepath.Join(_dir, "home")
+	if _err := os.MkdirAll(_home, 0755); _err != nil {
+		t.Fatalf("unable to create %q: %s", _home, _err.Error())
+	}
+	os.Setenv("HOME", _home)
+
+	// ensure each scope modifies the file of highest priority
+	_gitdir := filepath.Join(_dir, ".git")
+	for _, _test := range []struct {
+		scope gitconfig.Scope
+		path  string
+	}{
+		{gitconfig.GlobalScope, filepath.Join(_home, ".gitconfig")},
+		{gitconfig.LocalScope, filepath.Join(_gitdir, "config")},
+		{gitconfig.WorktreeScope, filepath.Join(_gitdir, "config")},
+	} {
+		_writer, _err := gitconfig.NewWriter(_test.scope, _dir)
+		if _err != nil {
+			t.Fatalf(
+				"%s: unexpected error from NewWriter(): %s",
+				_test.scope, _err.Error(),
+			)
+		} else if _writer.Scope() != _test.scope {
+			t.Fatalf(
+				"unexpected scope; expected %s, got %s",
+				_test.scope, _writer.Scope(),
+			)
+		}
+
+		_name := "test." + _test.scope.String()
+		if _err = _writer.Set(_name, "value"); _err != nil {
+			t.Fatalf(
+				"%s: unexpected error from Set(): %s",
+				_test.scope, _err.Error(),
+			)
+		}
+		_config, _err := gitconfig.NewConfigFromFile(_test.path)
+		if _err != nil {
+			t.Fatalf(
+				"%s: unexpected error from NewConfigFromFile(): %s",
+				_test.scope, _err.Error(),
+			)
+		} else if _property := _config.Get(_name); _property == nil {
+			t.Fatalf("%s: %q not written to %q", _test.scope, _name, _test.path)
+		}
+	}
+
+	// ensure the XDG configuration file is used if it is the only file
+	_xdg := filepath.Join(_home, ".config", "git", "config")
+	write(t, _xdg, "")
+	os.Remove(filepath.Join(_home, ".gitconfig"))
+	_writer, _err := gitconfig.NewWriter(gitconfig.GlobalScope, "")
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWriter(): %s", _err.Error())
+	} else if _err = _writer.Set("test.xdg", "value"); _err != nil {
+		t.Fatalf("unexpected error from Set(): %s", _err.Error())
+	}
+	_config, _err := gitconfig.NewConfigFromFile(_xdg)
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from NewConfigFromFile(): %s",
+			_err.Error(),
+		)
+	} else if _config.Get("test.xdg") == nil {
+		t.Fatalf("%q not written to %q", "test.xdg", _xdg)
+	}
+
+	// ensure a scope without a configuration file is rejected
+	os.Setenv("GIT_CONFIG_NOSYSTEM", "true")
+	_, _err = gitconfig.NewWriter(gitconfig.SystemScope, "")
+	if _err != gitconfig.MissingGitError {
+		t.Fatalf(
+			"unexpected error; expected %q, got %v",
+			gitconfig.MissingGitError, _err,
+		)
+	}
+} // TestNativeWriter()
+
+//
+// helper functions
+//
+
+// writer exercises the Writer w, using load to retrieve the configuration
+// modified by w.
+func writer(t *testing.T, w gitconfig.Writer, load func() gitconfig.Config) {
+	// set and then replace a property
+	for _, _value := range []string{"A. N. Other", "-leading dash"} {
+		if _err := w.Set("user.name", _value); _err != nil {
+			t.Fatalf("%s: unexpected error from Set(): %s", w.Scope(), _err)
+		}
+		_all := load().GetAll("user.name")
+		if len(_all) != 1 || _all[0].String() != _value {
+			t.Fatalf("%s: unexpected user.name after Set()", w.Scope())
+		}
+	}
+
+	// add multiple values to a property
+	_values := []string{"cache", "store", " spaced\n"}
+	for _, _value := range _values {
+		if _err := w.Add("credential.helper", _value); _err != nil {
+			t.Fatalf("%s: unexpected error from Add(): %s", w.Scope(), _err)
+		}
+	}
+	_all := load().GetAll("credential.helper")
+	if len(_all) != len(_values) {
+		t.Fatalf(
+			"%s: unexpected value count; expected %d, got %d",
+			w.Scope(), len(_values), len(_all),
+		)
+	}
+	for _i, _property := range _all {
+		if _property.String() != _values[_i] {
+			t.Fatalf(
+				"%s: unexpected value; expected %q, got %q",
+				w.Scope(), _values[_i], _property.String(),
+			)
+		}
+	}
+
+	// Unset() should fail for multi-valued properties
+	if _err := w.Unset("credential.helper"); _err == nil {
+		t.Fatalf("%s: expected error from Unset(); none found", w.Scope())
+	}
+	if _err := w.UnsetAll("credential.helper"); _err != nil {
+		t.Fatalf("%s: unexpected error from UnsetAll(): %s", w.Scope(), _err)
+	} else if load().Get("credential.helper") != nil {
+		t.Fatalf("%s: unexpected credential.helper property", w.Scope())
+	}
+	if _err := w.Unset("user.name"); _err != nil {
+		t.Fatalf("%s: unexpected error from Unset(): %s", w.Scope(), _err)
+	} else if load().Get("user.name") != nil {
+		t.Fatalf("%s: unexpected user.name property", w.Scope())
+	}
+
+	// rename and then remove a section
+	if _err := w.Set("remote.old.url", "https://example.com/"); _err != nil {
+		t.Fatalf("%s: unexpected error from Set(): %s", w.Scope(), _err)
+	}
+	_err := w.RenameSection("remote.old", "remote.new")
+	if _err != nil {
+		t.Fatalf(
+			"%s: unexpected error from RenameSection(): %s",
+			w.Scope(), _err,
+		)
+	}
+	_config := load()
+	if _config.Get("remote.old.url") != nil {
+		t.Fatalf("%s: unexpected remote.old.url property", w.Scope())
+	} else if _config.Get("remote.new.url") == nil {
+		t.Fatalf("%s: expected remote.new.url property", w.Scope())
+	}
+	if _err := w.RemoveSection("remote.new"); _err != nil {
+		t.Fatalf(
+			"%s: unexpected error from RemoveSection(): %s",
+			w.Scope(), _err,
+		)
+	} else if load().Get("remote.new.url") != nil {
+		t.Fatalf("%s: unexpected remote.new.url property", w.Scope())
+	}
+} // writer()
+
+// unset unsets the given environment variables, returning a function
+// that restores their original values.
+func unset(names ...string) func() {
+	_original := make(map[string]*string)
+	for _, _name := range names {
+		if _value, _ok := os.LookupEnv(_name); _ok {
+			_original[_name] = &_value
+		} else {
+			_original[_name] = nil
+		}
+		os.Unsetenv(_name)
+	}
+
+	return func() {
+		for _name, _value := range _original {
+			if _value == nil {
+				os.Unsetenv(_name)
+			} else {
+				os.Setenv(_name, *_value)
+			}
+		}
+	}
+} // unset()